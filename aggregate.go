@@ -0,0 +1,409 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Facet runs every facet pipeline on the documents matching filter in a single $facet aggregation.
+// The results are keyed by facet name.
+func (mf *Model) Facet(ctx context.Context, filter bson.M, facets map[string]mongo.Pipeline) (map[string][]bson.Raw, error) {
+	filter = mf.interceptQuery(filter)
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	facetStage := bson.D{}
+	for name, pipeline := range facets {
+		facetStage = append(facetStage, bson.E{Key: name, Value: pipeline})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: facetStage}},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("Facet", filter, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string][]bson.Raw
+	if err = cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return map[string][]bson.Raw{}, nil
+	}
+
+	return results[0], nil
+}
+
+// FacetT works like Facet but decodes the results of every facet into T.
+func FacetT[T any](ctx context.Context, mf *Model, filter bson.M, facets map[string]mongo.Pipeline) (map[string][]T, error) {
+
+	raws, err := mf.Facet(ctx, filter, facets)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string][]T, len(raws))
+	for name, docs := range raws {
+		decoded := make([]T, len(docs))
+		for i, doc := range docs {
+			if err = bson.Unmarshal(doc, &decoded[i]); err != nil {
+				return nil, err
+			}
+		}
+		results[name] = decoded
+	}
+
+	return results, nil
+}
+
+type GraphLookupParams struct {
+	From                    string
+	StartWith               interface{}
+	ConnectFromField        string
+	ConnectToField          string
+	As                      string
+	MaxDepth                *int
+	DepthField              *string
+	RestrictSearchWithMatch bson.M
+	Filter                  bson.M
+}
+
+// GraphLookup runs a recursive $graphLookup on the documents matching params.Filter.
+func (mf *Model) GraphLookup(ctx context.Context, params GraphLookupParams, results interface{}) error {
+
+	if params.From == "" || params.StartWith == nil || params.ConnectFromField == "" || params.ConnectToField == "" || params.As == "" {
+		return errors.New("graph lookup requires From, StartWith, ConnectFromField, ConnectToField and As")
+	}
+
+	filter := mf.interceptQuery(params.Filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	graphLookup := bson.D{
+		{Key: "from", Value: params.From},
+		{Key: "startWith", Value: params.StartWith},
+		{Key: "connectFromField", Value: params.ConnectFromField},
+		{Key: "connectToField", Value: params.ConnectToField},
+		{Key: "as", Value: params.As},
+	}
+
+	if params.MaxDepth != nil {
+		graphLookup = append(graphLookup, bson.E{Key: "maxDepth", Value: *params.MaxDepth})
+	}
+
+	if params.DepthField != nil {
+		graphLookup = append(graphLookup, bson.E{Key: "depthField", Value: *params.DepthField})
+	}
+
+	if params.RestrictSearchWithMatch != nil {
+		graphLookup = append(graphLookup, bson.E{Key: "restrictSearchWithMatch", Value: params.RestrictSearchWithMatch})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$graphLookup", Value: graphLookup}},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("GraphLookup", filter, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	return cur.All(ctx, results)
+}
+
+type DateBucket struct {
+	Period time.Time `bson:"_id"`
+	Count  int64     `bson:"count"`
+	// Fields holds the values of the additional accumulators.
+	Fields bson.M `bson:",inline"`
+}
+
+// DateTruncAggregate groups the documents matching filter into buckets of dateField truncated to unit,
+// sorted by period. Unit is one of hour, day, week, month, quarter or year.
+// additionalGroupFields are added as accumulators to the $group stage. It requires MongoDB 5.0+.
+func (mf *Model) DateTruncAggregate(ctx context.Context, dateField string, unit string, filter bson.M, additionalGroupFields bson.D) ([]DateBucket, error) {
+	filter = mf.interceptQuery(filter)
+
+	switch unit {
+	case "hour", "day", "week", "month", "quarter", "year":
+	default:
+		return nil, fmt.Errorf("invalid date unit: %s", unit)
+	}
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	group := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{{Key: "date", Value: "$" + dateField}, {Key: "unit", Value: unit}}}}},
+		{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+	}
+	group = append(group, additionalGroupFields...)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: group}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("DateTruncAggregate", filter, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := []DateBucket{}
+	if err = cur.All(ctx, &buckets); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+func unwindStage(arrayField string, preserveNull bool, includeArrayIndex bool) bson.D {
+	unwind := bson.D{
+		{Key: "path", Value: "$" + arrayField},
+		{Key: "preserveNullAndEmptyArrays", Value: preserveNull},
+	}
+
+	if includeArrayIndex {
+		unwind = append(unwind, bson.E{Key: "includeArrayIndex", Value: arrayField + "Index"})
+	}
+
+	return bson.D{{Key: "$unwind", Value: unwind}}
+}
+
+// FindUnwound returns one document per element of arrayField for the documents matching filter.
+// When includeArrayIndex is set the element position is stored in the "<arrayField>Index" field.
+func (mf *Model) FindUnwound(ctx context.Context, filter bson.M, arrayField string, preserveNull bool, includeArrayIndex bool, results interface{}) error {
+	filter = mf.interceptQuery(filter)
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		unwindStage(arrayField, preserveNull, includeArrayIndex),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindUnwound", filter, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	return cur.All(ctx, results)
+}
+
+// UnwindAndGroup unwinds arrayField and groups the resulting documents by the groupBy field.
+// accumulator holds the accumulator expressions of the $group stage, keyed by output field.
+func (mf *Model) UnwindAndGroup(ctx context.Context, filter bson.M, arrayField string, groupBy string, accumulator bson.M) ([]bson.M, error) {
+	filter = mf.interceptQuery(filter)
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	group := bson.M{"_id": "$" + groupBy}
+	for field, expr := range accumulator {
+		group[field] = expr
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		unwindStage(arrayField, false, false),
+		{{Key: "$group", Value: group}},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("UnwindAndGroup", filter, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []bson.M{}
+	if err = cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+type MergeOptions struct {
+	// On lists the fields identifying a document in the target collection, _id when empty.
+	On []string
+	// WhenMatched is one of merge, replace, keepExisting or fail.
+	WhenMatched string
+	// WhenNotMatched is one of insert, discard or fail.
+	WhenNotMatched string
+}
+
+// AggregateInto runs pipeline on the documents matching srcFilter and merges its output into targetCollection.
+func (mf *Model) AggregateInto(ctx context.Context, srcFilter bson.M, pipeline mongo.Pipeline, targetCollection string, mergeOptions MergeOptions) error {
+
+	if targetCollection == "" {
+		return errors.New("target collection can't be empty")
+	}
+
+	merge := bson.D{{Key: "into", Value: targetCollection}}
+
+	if len(mergeOptions.On) > 0 {
+		merge = append(merge, bson.E{Key: "on", Value: mergeOptions.On})
+	}
+
+	switch mergeOptions.WhenMatched {
+	case "":
+	case "merge", "replace", "keepExisting", "fail":
+		merge = append(merge, bson.E{Key: "whenMatched", Value: mergeOptions.WhenMatched})
+	default:
+		return fmt.Errorf("invalid whenMatched value: %s", mergeOptions.WhenMatched)
+	}
+
+	switch mergeOptions.WhenNotMatched {
+	case "":
+	case "insert", "discard", "fail":
+		merge = append(merge, bson.E{Key: "whenNotMatched", Value: mergeOptions.WhenNotMatched})
+	default:
+		return fmt.Errorf("invalid whenNotMatched value: %s", mergeOptions.WhenNotMatched)
+	}
+
+	srcFilter = mf.interceptQuery(srcFilter)
+	if srcFilter == nil {
+		srcFilter = bson.M{}
+	}
+
+	stages := mongo.Pipeline{{{Key: "$match", Value: srcFilter}}}
+	stages = append(stages, pipeline...)
+	stages = append(stages, bson.D{{Key: "$merge", Value: merge}})
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("AggregateInto", srcFilter, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, stages)
+	if err != nil {
+		return err
+	}
+
+	return cur.Close(ctx)
+}
+
+// RawAggregate runs pipeline and returns the output documents undecoded.
+func (mf *Model) RawAggregate(ctx context.Context, pipeline mongo.Pipeline) ([]bson.Raw, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("RawAggregate", nil, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []bson.Raw{}
+	if err = cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// RawAggregateIter is the streaming version of RawAggregate, cur.Current holds the raw document.
+// The caller must close the cursor.
+func (mf *Model) RawAggregateIter(ctx context.Context, pipeline mongo.Pipeline) (*Cursor, error) {
+
+	defer mf.logSlowQuery("RawAggregateIter", nil, time.Now())
+
+	return mf.col.Aggregate(ctx, pipeline)
+}
+
+// AggregateOptions configures AggregateWithOptions, zero values are left unset.
+type AggregateOptions struct {
+	AllowDiskUse bool
+	BatchSize    int32
+	MaxTime      time.Duration
+	Collation    *options.Collation
+	Comment      string
+	Hint         interface{}
+	// Let defines variables the pipeline can read as $$name.
+	Let bson.M
+	// Custom holds options unknown to the driver version in use, sent as is with the aggregate command.
+	Custom bson.M
+}
+
+func (o AggregateOptions) driverOptions() *options.AggregateOptions {
+	aggregateOptions := options.Aggregate()
+	if o.AllowDiskUse {
+		aggregateOptions.SetAllowDiskUse(true)
+	}
+	if o.BatchSize > 0 {
+		aggregateOptions.SetBatchSize(o.BatchSize)
+	}
+	if o.MaxTime > 0 {
+		aggregateOptions.SetMaxTime(o.MaxTime)
+	}
+	if o.Collation != nil {
+		aggregateOptions.SetCollation(o.Collation)
+	}
+	if o.Comment != "" {
+		aggregateOptions.SetComment(o.Comment)
+	}
+	if o.Hint != nil {
+		aggregateOptions.SetHint(o.Hint)
+	}
+	if o.Let != nil {
+		aggregateOptions.SetLet(o.Let)
+	}
+	if o.Custom != nil {
+		aggregateOptions.SetCustom(o.Custom)
+	}
+	return aggregateOptions
+}
+
+// AggregateWithOptions runs pipeline with opts and decodes the documents it produces into results.
+func (mf *Model) AggregateWithOptions(ctx context.Context, pipeline mongo.Pipeline, opts AggregateOptions, results interface{}) error {
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("AggregateWithOptions", nil, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline, opts.driverOptions())
+	if err != nil {
+		return err
+	}
+
+	return mf.decodeAll(ctx, cur, results)
+}
@@ -0,0 +1,141 @@
+package yamgo
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AutoIndexMode selects what Find and FindOne do when their filter is answered by a collection scan.
+type AutoIndexMode int
+
+const (
+	// AutoIndexDisabled does not analyze the queries.
+	AutoIndexDisabled AutoIndexMode = iota
+	// AutoIndexWarnOnly logs a warning for every filter shape answered by a collection scan.
+	AutoIndexWarnOnly
+	// AutoIndexCreate logs a warning and creates an ascending index on the filter fields in the background.
+	AutoIndexCreate
+)
+
+// WithAutoCreateIndexes explains the filters of Find and FindOne and reacts to collection scans according to mode.
+// Every filter shape is analyzed once. Warnings go to the slow query logger if set, to slog.Default otherwise.
+func WithAutoCreateIndexes(mode AutoIndexMode) Option {
+	return func(m *Model) error {
+		m.autoIndexMode = mode
+		m.autoIndexChecked = &sync.Map{}
+		return nil
+	}
+}
+
+func (mf *Model) checkIndexUsage(op string, filter bson.M) {
+	if mf.autoIndexMode == AutoIndexDisabled {
+		return
+	}
+
+	fields := filterFields(filter)
+	if len(fields) == 0 {
+		return
+	}
+
+	if _, checked := mf.autoIndexChecked.LoadOrStore(strings.Join(fields, ","), true); checked {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), MediumTimeout*time.Second)
+	defer cancel()
+
+	logger := mf.slowQueryLogger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	var explain bson.Raw
+	err := mf.col.Database().RunCommand(ctx, bson.D{
+		{Key: "explain", Value: bson.D{{Key: "find", Value: mf.col.Name()}, {Key: "filter", Value: filter}}},
+		{Key: "verbosity", Value: "queryPlanner"},
+	}).Decode(&explain)
+
+	if err != nil {
+		logger.Error("yamgo: explain failed", slog.String("op", op), slog.String("collection", mf.col.Name()), slog.Any("error", err))
+		return
+	}
+
+	winningPlan, err := explain.LookupErr("queryPlanner", "winningPlan")
+	if err != nil || !hasCollectionScan(winningPlan) {
+		return
+	}
+
+	logger.Warn("yamgo: collection scan",
+		slog.String("op", op),
+		slog.String("collection", mf.col.Name()),
+		slog.Any("filter", mf.sanitizeFilter(filter)),
+		slog.Any("fields", fields),
+	)
+
+	if mf.autoIndexMode != AutoIndexCreate {
+		return
+	}
+
+	keys := bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: 1})
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), LongTimeout*time.Second)
+		defer cancel()
+
+		if _, err := mf.col.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys}); err != nil {
+			logger.Error("yamgo: index creation failed", slog.String("collection", mf.col.Name()), slog.Any("error", err))
+		}
+	}()
+}
+
+// filterFields returns the sorted field names of filter, skipping operators like $or.
+func filterFields(filter bson.M) []string {
+	fields := make([]string, 0, len(filter))
+	for field := range filter {
+		if !strings.HasPrefix(field, "$") {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// hasCollectionScan reports whether a COLLSCAN stage appears anywhere in plan.
+func hasCollectionScan(plan bson.RawValue) bool {
+	switch plan.Type {
+	case bson.TypeEmbeddedDocument:
+		elems, err := plan.Document().Elements()
+		if err != nil {
+			return false
+		}
+		for _, elem := range elems {
+			if stage, ok := elem.Value().StringValueOK(); ok && elem.Key() == "stage" && stage == "COLLSCAN" {
+				return true
+			}
+			if hasCollectionScan(elem.Value()) {
+				return true
+			}
+		}
+	case bson.TypeArray:
+		values, err := plan.Array().Values()
+		if err != nil {
+			return false
+		}
+		for _, value := range values {
+			if hasCollectionScan(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
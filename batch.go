@@ -0,0 +1,146 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BatchTimeout is the default time budget shared by all the operations of a Batch.
+const BatchTimeout time.Duration = 30 * time.Second
+
+// WithBatchTimeout sets the time budget shared by all the operations of a Batch.
+func WithBatchTimeout(d time.Duration) Option {
+	return func(m *Model) error {
+		if d <= 0 {
+			return errors.New("batch timeout must be greater than zero")
+		}
+		m.batchTimeout = d
+		return nil
+	}
+}
+
+// BatchContext runs model operations under the single deadline of a Batch.
+// Once the deadline has passed every operation fails immediately with context.DeadlineExceeded.
+type BatchContext struct {
+	ctx   context.Context
+	model *Model
+}
+
+// Batch calls fn with a BatchContext whose operations share one context derived from ctx,
+// bounded by the model batch timeout.
+func (mf *Model) Batch(ctx context.Context, fn func(b BatchContext) error) error {
+	timeout := mf.batchTimeout
+	if timeout == 0 {
+		timeout = BatchTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return fn(BatchContext{ctx: ctx, model: mf})
+}
+
+// Context returns the shared context, for the model methods that take a context directly.
+func (b BatchContext) Context() context.Context {
+	return b.ctx
+}
+
+func (b BatchContext) FindOne(filter bson.M, result interface{}, opts ...FindOption) error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	return b.model.findOne(b.ctx, filter, result, opts...)
+}
+
+func (b BatchContext) FindByID(id string, result interface{}, opts ...FindOption) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	return b.FindOne(bson.M{"_id": objectID}, result, opts...)
+}
+
+func (b BatchContext) Find(filter bson.M, results interface{}, opts ...FindOption) error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	return b.model.find(b.ctx, filter, results, opts...)
+}
+
+func (b BatchContext) FindWithOptions(filter bson.M, option options.FindOptions, results interface{}) error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	return b.model.FindWithOptionsContext(b.ctx, filter, option, results)
+}
+
+func (b BatchContext) FindAndPopulate(filter bson.M, option options.FindOptions, populate []PopulateOptions, results interface{}) error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	return b.model.FindAndPopulateContext(b.ctx, filter, option, populate, results)
+}
+
+func (b BatchContext) PaginatedFind(params PaginationFindParams, results interface{}) (Page, error) {
+	if err := b.ctx.Err(); err != nil {
+		return Page{}, err
+	}
+	return b.model.PaginatedFindContext(b.ctx, params, results)
+}
+
+func (b BatchContext) Aggregate(pipeline mongo.Pipeline, results interface{}) error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	return b.model.AggregateContext(b.ctx, pipeline, results)
+}
+
+func (b BatchContext) InsertOne(record interface{}) (*mongo.InsertOneResult, error) {
+	if err := b.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return b.model.insertOne(b.ctx, record)
+}
+
+func (b BatchContext) InsertMany(records []interface{}) (*mongo.InsertManyResult, error) {
+	if err := b.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return b.model.insertMany(b.ctx, records)
+}
+
+func (b BatchContext) CountDocuments(filter bson.M) (int, error) {
+	if err := b.ctx.Err(); err != nil {
+		return 0, err
+	}
+	count, err := b.model.Count(b.ctx, filter)
+	return int(count), err
+}
+
+func (b BatchContext) Exists(filter bson.M) (bool, error) {
+	if err := b.ctx.Err(); err != nil {
+		return false, err
+	}
+	return b.model.Exists(b.ctx, filter)
+}
+
+func (b BatchContext) FindOneAndModify(filter bson.M, modification interface{}, result interface{}, opts ...FindOneAndModifyOption) error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	return b.model.FindOneAndModify(b.ctx, filter, modification, result, opts...)
+}
+
+func (b BatchContext) DeleteMany(filter bson.M) (*mongo.DeleteResult, error) {
+	if err := b.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return b.model.DeleteMany(b.ctx, filter)
+}
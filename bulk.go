@@ -0,0 +1,102 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkWriteError is the failure of a single write of a bulk write.
+type BulkWriteError struct {
+	// Index is the position of the write in the models passed to BulkWrite.
+	Index   int
+	Code    int
+	Message string
+	// Document is the inserted or replacement document, or the write model for updates and deletes.
+	Document interface{}
+}
+
+// BulkWriteReport reports the outcome of every write of a bulk write.
+type BulkWriteReport struct {
+	Result      *mongo.BulkWriteResult
+	WriteErrors []BulkWriteError
+
+	succeeded int
+}
+
+// Succeeded returns the number of writes applied.
+func (r BulkWriteReport) Succeeded() int {
+	return r.succeeded
+}
+
+// Failed returns the number of writes which failed.
+func (r BulkWriteReport) Failed() int {
+	return len(r.WriteErrors)
+}
+
+// IsDuplicateKeyError reports whether bwe was caused by a unique index violation.
+func IsDuplicateKeyError(bwe BulkWriteError) bool {
+	return mongo.IsDuplicateKeyError(mongo.WriteError{Code: bwe.Code, Message: bwe.Message})
+}
+
+// BulkWrite runs models in a single bulk write. When some writes fail, the returned report lists them
+// along with the driver error. Ordered bulk writes stop at the first failure, unordered ones carry on.
+func (mf *Model) BulkWrite(ctx context.Context, models []mongo.WriteModel, ordered bool) (BulkWriteReport, error) {
+
+	if len(models) == 0 {
+		return BulkWriteReport{}, errors.New("at least one write model is required")
+	}
+
+	for _, model := range models {
+		if insert, ok := model.(*mongo.InsertOneModel); ok {
+			if err := mf.runBeforeInsert(insert.Document); err != nil {
+				return BulkWriteReport{}, err
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("BulkWrite", nil, time.Now())
+
+	res, err := mf.col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered))
+
+	var bwe mongo.BulkWriteException
+	if err != nil && !errors.As(err, &bwe) {
+		return BulkWriteReport{}, err
+	}
+
+	report := BulkWriteReport{Result: res, succeeded: len(models)}
+	for _, we := range bwe.WriteErrors {
+		report.WriteErrors = append(report.WriteErrors, BulkWriteError{
+			Index:    we.Index,
+			Code:     we.Code,
+			Message:  we.Message,
+			Document: writeModelDocument(models[we.Index]),
+		})
+	}
+
+	if len(report.WriteErrors) > 0 {
+		if ordered {
+			report.succeeded = report.WriteErrors[0].Index
+		} else {
+			report.succeeded -= len(report.WriteErrors)
+		}
+	}
+
+	return report, err
+}
+
+func writeModelDocument(model mongo.WriteModel) interface{} {
+	switch m := model.(type) {
+	case *mongo.InsertOneModel:
+		return m.Document
+	case *mongo.ReplaceOneModel:
+		return m.Replacement
+	default:
+		return model
+	}
+}
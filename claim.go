@@ -0,0 +1,66 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ClaimExpiryField stores when the claim set by FindWithClaim lapses.
+const ClaimExpiryField = "claimExpiry"
+
+// FindWithClaim atomically claims a document matching filter that is unclaimed, or whose claim expired,
+// by setting claimField to claimValue for claimDuration, and decodes it into result.
+// It returns false when there is no such document.
+func (mf *Model) FindWithClaim(ctx context.Context, filter bson.M, claimField string, claimValue interface{}, claimDuration time.Duration, result interface{}) (bool, error) {
+	if claimField == "" {
+		return false, errors.New("claim field can't be empty")
+	}
+
+	now := time.Now()
+
+	unclaimed := bson.M{"$or": bson.A{
+		bson.M{claimField: bson.M{"$exists": false}},
+		bson.M{ClaimExpiryField: bson.M{"$lte": now}},
+	}}
+	if len(filter) > 0 {
+		unclaimed = bson.M{"$and": bson.A{filter, unclaimed}}
+	}
+
+	claim := bson.M{"$set": bson.M{
+		claimField:       claimValue,
+		ClaimExpiryField: now.Add(claimDuration),
+	}}
+
+	err := mf.FindOneAndModify(ctx, unclaimed, claim, result, ModifyReturnNew())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseClaim removes the claim set by FindWithClaim from the documents matching filter.
+func (mf *Model) ReleaseClaim(ctx context.Context, filter bson.M, claimField string) error {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	if claimField == "" {
+		return errors.New("claim field can't be empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("ReleaseClaim", filter, time.Now())
+
+	_, err := mf.col.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{claimField: "", ClaimExpiryField: ""}})
+	return err
+}
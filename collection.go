@@ -0,0 +1,54 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CollectionExists reports whether the database holds a collection called name.
+func CollectionExists(ctx context.Context, name string) (bool, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, ShortTimeout*time.Second)
+	defer cancel()
+
+	names, err := _mongo.Database.ListCollectionNames(ctx, bson.M{"name": name})
+	if err != nil {
+		return false, err
+	}
+
+	return len(names) > 0, nil
+}
+
+// RenameCollection renames the model collection to newName within the same database, replacing an existing
+// newName collection if dropTarget is set. The model uses the renamed collection afterwards.
+func (mf *Model) RenameCollection(ctx context.Context, newName string, dropTarget bool) error {
+
+	if newName == "" {
+		return errors.New("new collection name can't be empty")
+	}
+
+	db := mf.col.Database()
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+
+	err := db.Client().Database("admin").RunCommand(ctx, bson.D{
+		{Key: "renameCollection", Value: db.Name() + "." + mf.col.Name()},
+		{Key: "to", Value: db.Name() + "." + newName},
+		{Key: "dropTarget", Value: dropTarget},
+	}).Err()
+	if err != nil {
+		return err
+	}
+
+	if collectionOptions := mf.collectionOptions(); collectionOptions != nil {
+		mf.col = db.Collection(newName, collectionOptions)
+	} else {
+		mf.col = db.Collection(newName)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package yamgo
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidCompressor = errors.New("invalid compressor")
+
+// WithCompression enables wire protocol compression using the given algorithms, in order of preference.
+// Supported algorithms are snappy, zlib and zstd.
+func WithCompression(algorithms ...string) ClientOption {
+	return func(o *options.ClientOptions) error {
+		for _, algorithm := range algorithms {
+			switch algorithm {
+			case "snappy", "zlib", "zstd":
+			default:
+				return fmt.Errorf("%w: %q", ErrInvalidCompressor, algorithm)
+			}
+		}
+		o.SetCompressors(algorithms)
+		return nil
+	}
+}
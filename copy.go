@@ -0,0 +1,53 @@
+package yamgo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const copyBatchSize = 1000
+
+// CopyTo streams the documents matching filter into target, inserting them in batches of 1000.
+// It returns the number of documents copied.
+func (mf *Model) CopyTo(ctx context.Context, filter bson.M, target *Model) (int64, error) {
+
+	var copied int64
+
+	err := mf.FindInBatches(ctx, filter, copyBatchSize, func(batch []bson.Raw, batchNum int) error {
+		writes := make([]mongo.WriteModel, len(batch))
+		for i, doc := range batch {
+			if err := target.runBeforeInsert(doc); err != nil {
+				return err
+			}
+			writes[i] = mongo.NewInsertOneModel().SetDocument(doc)
+		}
+
+		writeCtx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+		defer cancel()
+
+		res, err := target.col.BulkWrite(writeCtx, writes)
+		if res != nil {
+			copied += res.InsertedCount
+		}
+		return err
+	})
+
+	return copied, err
+}
+
+// MoveTo copies the documents matching filter into target and then deletes them from the model collection.
+// Documents matching filter that are inserted while the copy is in progress are deleted as well.
+func (mf *Model) MoveTo(ctx context.Context, filter bson.M, target *Model) (int64, error) {
+
+	moved, err := mf.CopyTo(ctx, filter, target)
+	if err != nil {
+		return moved, err
+	}
+
+	_, err = mf.DeleteMany(ctx, filter)
+
+	return moved, err
+}
@@ -5,18 +5,73 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func (mf *Model) CountDocuments(filter bson.M) (int, error) {
+// CountDocuments counts the documents matching filter. The filter is sent as is, a nil filter counts all the documents.
+// Count is the context aware equivalent.
+func (mf *Model) CountDocuments(filter bson.M, opts ...FindOption) (int, error) {
+	count, err := mf.Count(context.Background(), filter, opts...)
+	return int(count), err
+}
+
+// Count counts the documents matching filter, a nil filter counts all the documents.
+func (mf *Model) Count(ctx context.Context, filter bson.M, opts ...FindOption) (n int64, err error) {
+	filter = mf.interceptQuery(filter)
+
+	o, err := applyFindOptions(opts)
+	if err != nil {
+		return 0, err
+	}
+	filter = mf.scopeDeleted(filter, o.includeDeleted)
 
-	ctx, cancel := context.WithTimeout(context.Background(), LongTimeout*time.Second)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
 	defer cancel()
+	defer mf.logSlowQuery("CountDocuments", filter, time.Now())
+	defer func(start time.Time) {
+		mf.observe("CountDocuments", filter, nil, n, start, err)
+	}(time.Now())
 
 	count, err := mf.col.CountDocuments(ctx, filter)
 
+	if err != nil {
+		return 0, mf.wrapQueryError("CountDocuments", filter, err)
+	}
+
+	return count, nil
+}
+
+// CountDocumentsWithPipeline counts the documents produced by pipeline, e.g. after an $unwind.
+func (mf *Model) CountDocumentsWithPipeline(ctx context.Context, pipeline mongo.Pipeline) (int64, error) {
+
+	stages := make(mongo.Pipeline, 0, len(pipeline)+1)
+	stages = append(stages, pipeline...)
+	stages = append(stages, bson.D{{Key: "$count", Value: "count"}})
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("CountDocumentsWithPipeline", nil, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, stages)
 	if err != nil {
 		return 0, err
 	}
 
-	return int(count), nil
+	var counts []struct {
+		Count int64 `bson:"count"`
+	}
+	if err = cur.All(ctx, &counts); err != nil {
+		return 0, err
+	}
+
+	// $count outputs nothing when the pipeline is empty
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	return counts[0].Count, nil
 }
@@ -0,0 +1,62 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCSFLENotConfigured is returned when a data key is requested from a client connected without WithCSFLE.
+var ErrCSFLENotConfigured = errors.New("client-side field level encryption is not configured")
+
+// WithCSFLE enables automatic client-side field level encryption of the fields described by schemaMap,
+// keyed by "<database>.<collection>". Data keys are stored in keyVaultNamespace ("<database>.<collection>").
+// The binary must be built with the cse tag and libmongocrypt available.
+func WithCSFLE(kmsProviders map[string]map[string]interface{}, keyVaultNamespace string, schemaMap bson.M) ClientOption {
+	return func(o *options.ClientOptions) error {
+		if len(kmsProviders) == 0 {
+			return errors.New("at least one kms provider is required")
+		}
+		if keyVaultNamespace == "" {
+			return errors.New("key vault namespace can't be empty")
+		}
+
+		o.SetAutoEncryptionOptions(options.AutoEncryption().
+			SetKmsProviders(kmsProviders).
+			SetKeyVaultNamespace(keyVaultNamespace).
+			SetSchemaMap(schemaMap))
+		return nil
+	}
+}
+
+// CreateDataEncryptionKey creates a data encryption key in the key vault configured by WithCSFLE
+// and returns its id, to be referenced in the encryption schema.
+func CreateDataEncryptionKey(ctx context.Context, kmsProvider string, masterKeyOpts bson.M) (primitive.Binary, error) {
+	autoEncryption := _mongo.autoEncryption
+	if autoEncryption == nil {
+		return primitive.Binary{}, ErrCSFLENotConfigured
+	}
+
+	clientEncryption, err := mongo.NewClientEncryption(_mongo.client, options.ClientEncryption().
+		SetKmsProviders(autoEncryption.KmsProviders).
+		SetKeyVaultNamespace(autoEncryption.KeyVaultNamespace))
+	if err != nil {
+		return primitive.Binary{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer clientEncryption.Close(ctx)
+
+	dataKeyOptions := options.DataKey()
+	if masterKeyOpts != nil {
+		dataKeyOptions.SetMasterKey(masterKeyOpts)
+	}
+
+	return clientEncryption.CreateDataKey(ctx, kmsProvider, dataKeyOptions)
+}
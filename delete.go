@@ -0,0 +1,72 @@
+package yamgo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (mf *Model) DeleteMany(ctx context.Context, filter bson.M) (res *mongo.DeleteResult, err error) {
+	filter = mf.interceptQuery(filter)
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("DeleteMany", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = res.DeletedCount
+		}
+		mf.observe("DeleteMany", filter, nil, count, start, err)
+	}(time.Now())
+
+	res, err = mf.col.DeleteMany(ctx, filter)
+	if err != nil {
+		return nil, mf.wrapQueryError("DeleteMany", filter, err)
+	}
+
+	return res, nil
+}
+
+// DeleteOne deletes the first document matching filter.
+func (mf *Model) DeleteOne(ctx context.Context, filter bson.M) (res *mongo.DeleteResult, err error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("DeleteOne", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = res.DeletedCount
+		}
+		mf.observe("DeleteOne", filter, nil, count, start, err)
+	}(time.Now())
+
+	res, err = mf.col.DeleteOne(ctx, filter)
+	if err != nil {
+		return nil, mf.wrapQueryError("DeleteOne", filter, err)
+	}
+
+	return res, nil
+}
+
+// ClearCollection deletes every document of the collection, keeping its indexes and validators.
+func (mf *Model) ClearCollection(ctx context.Context) error {
+	_, err := mf.DeleteMany(ctx, bson.M{})
+	return err
+}
+
+// ClearIf deletes the documents matching filter and returns how many were deleted.
+func (mf *Model) ClearIf(ctx context.Context, filter bson.M) (int64, error) {
+	res, err := mf.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,264 @@
+package yamgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultDiffDepth is the nesting depth DiffDocuments descends into.
+const DefaultDiffDepth = 32
+
+type FieldChange struct {
+	Before interface{}
+	After  interface{}
+}
+
+// DocumentDiff describes the changes between two documents, keyed by dotted field path.
+type DocumentDiff struct {
+	Added     map[string]interface{}
+	Removed   map[string]interface{}
+	Modified  map[string]FieldChange
+	Unchanged []string
+}
+
+// IsEmpty reports whether the two documents are equal.
+func (d DocumentDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// DiffDocuments computes the differences between before and after, descending into nested documents.
+func DiffDocuments(before, after bson.M) DocumentDiff {
+	return DiffDocumentsDepth(before, after, DefaultDiffDepth)
+}
+
+// DiffDocumentsDepth is like DiffDocuments but nested documents deeper than maxDepth are compared as a whole.
+func DiffDocumentsDepth(before, after bson.M, maxDepth int) DocumentDiff {
+	diff := DocumentDiff{
+		Added:     map[string]interface{}{},
+		Removed:   map[string]interface{}{},
+		Modified:  map[string]FieldChange{},
+		Unchanged: []string{},
+	}
+
+	diffDocuments(&diff, "", before, after, maxDepth)
+	sort.Strings(diff.Unchanged)
+
+	return diff
+}
+
+func diffDocuments(diff *DocumentDiff, prefix string, before, after bson.M, depth int) {
+	for key, beforeValue := range before {
+		path := prefix + key
+
+		afterValue, ok := after[key]
+		if !ok {
+			diff.Removed[path] = beforeValue
+			continue
+		}
+
+		beforeDoc, beforeIsDoc := beforeValue.(bson.M)
+		afterDoc, afterIsDoc := afterValue.(bson.M)
+
+		switch {
+		case beforeIsDoc && afterIsDoc && depth > 0:
+			diffDocuments(diff, path+".", beforeDoc, afterDoc, depth-1)
+		case reflect.DeepEqual(beforeValue, afterValue):
+			diff.Unchanged = append(diff.Unchanged, path)
+		default:
+			diff.Modified[path] = FieldChange{Before: beforeValue, After: afterValue}
+		}
+	}
+
+	for key, afterValue := range after {
+		if _, ok := before[key]; !ok {
+			diff.Added[prefix+key] = afterValue
+		}
+	}
+}
+
+// DiffAsMongoUpdate converts diff into an update document applying it with $set and $unset.
+func DiffAsMongoUpdate(diff DocumentDiff) bson.M {
+	update := bson.M{}
+
+	set := bson.M{}
+	for path, value := range diff.Added {
+		set[path] = value
+	}
+	for path, change := range diff.Modified {
+		set[path] = change.After
+	}
+
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+
+	if len(diff.Removed) > 0 {
+		unset := bson.M{}
+		for path := range diff.Removed {
+			unset[path] = ""
+		}
+		update["$unset"] = unset
+	}
+
+	return update
+}
+
+const diffBatchSize = 1000
+
+// CollectionDiff lists the keys of the documents that differ between two collections.
+type CollectionDiff struct {
+	OnlyInSource []primitive.ObjectID
+	OnlyInTarget []primitive.ObjectID
+	Different    []primitive.ObjectID
+}
+
+// IsEmpty reports whether the two collections hold the same documents.
+func (d CollectionDiff) IsEmpty() bool {
+	return len(d.OnlyInSource) == 0 && len(d.OnlyInTarget) == 0 && len(d.Different) == 0
+}
+
+// DiffCollections matches the documents of source and target on keyField, which must hold ObjectIDs,
+// and compares the documents present in both with DiffDocuments. When keyField is not _id the _id
+// fields are left out of the comparison, so that a migration may assign new IDs.
+func DiffCollections(ctx context.Context, source, target *Model, keyField string) (CollectionDiff, error) {
+	var diff CollectionDiff
+
+	sourceKeys, err := source.objectIDKeys(ctx, keyField)
+	if err != nil {
+		return diff, err
+	}
+
+	targetKeys, err := target.objectIDKeys(ctx, keyField)
+	if err != nil {
+		return diff, err
+	}
+
+	common := []primitive.ObjectID{}
+	for key := range sourceKeys {
+		if _, ok := targetKeys[key]; ok {
+			common = append(common, key)
+		} else {
+			diff.OnlyInSource = append(diff.OnlyInSource, key)
+		}
+	}
+	for key := range targetKeys {
+		if _, ok := sourceKeys[key]; !ok {
+			diff.OnlyInTarget = append(diff.OnlyInTarget, key)
+		}
+	}
+
+	for start := 0; start < len(common); start += diffBatchSize {
+		keys := common[start:min(start+diffBatchSize, len(common))]
+
+		sourceDocs, err := source.documentsByKey(ctx, keyField, keys)
+		if err != nil {
+			return diff, err
+		}
+
+		targetDocs, err := target.documentsByKey(ctx, keyField, keys)
+		if err != nil {
+			return diff, err
+		}
+
+		for _, key := range keys {
+			sourceDoc, targetDoc := sourceDocs[key], targetDocs[key]
+			if keyField != "_id" {
+				delete(sourceDoc, "_id")
+				delete(targetDoc, "_id")
+			}
+			if !DiffDocuments(sourceDoc, targetDoc).IsEmpty() {
+				diff.Different = append(diff.Different, key)
+			}
+		}
+	}
+
+	sortObjectIDs(diff.OnlyInSource)
+	sortObjectIDs(diff.OnlyInTarget)
+	sortObjectIDs(diff.Different)
+
+	return diff, nil
+}
+
+// objectIDKeys returns the keyField values of the documents of the model.
+func (mf *Model) objectIDKeys(ctx context.Context, keyField string) (map[primitive.ObjectID]struct{}, error) {
+	filter := mf.interceptQuery(bson.M{})
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+
+	cur, err := mf.col.Find(ctx, filter, options.Find().SetProjection(bson.M{keyField: 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	keys := map[primitive.ObjectID]struct{}{}
+	for cur.Next(ctx) {
+		key, err := objectIDKey(cur.Current, keyField)
+		if err != nil {
+			return nil, err
+		}
+		keys[key] = struct{}{}
+	}
+
+	return keys, cur.Err()
+}
+
+// documentsByKey returns the documents of the model whose keyField is in keys.
+func (mf *Model) documentsByKey(ctx context.Context, keyField string, keys []primitive.ObjectID) (map[primitive.ObjectID]bson.M, error) {
+	filter := mf.interceptQuery(bson.M{keyField: bson.M{"$in": keys}})
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+
+	cur, err := mf.col.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	docs := map[primitive.ObjectID]bson.M{}
+	for cur.Next(ctx) {
+		key, err := objectIDKey(cur.Current, keyField)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc bson.M
+		if err = bson.Unmarshal(cur.Current, &doc); err != nil {
+			return nil, err
+		}
+		docs[key] = doc
+	}
+
+	return docs, cur.Err()
+}
+
+func objectIDKey(doc bson.Raw, keyField string) (primitive.ObjectID, error) {
+	value, err := doc.LookupErr(strings.Split(keyField, ".")...)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("document %v has no %s: %w", doc.Lookup("_id"), keyField, err)
+	}
+
+	key, ok := value.ObjectIDOK()
+	if !ok {
+		return primitive.NilObjectID, fmt.Errorf("%s of document %v is not an ObjectID", keyField, doc.Lookup("_id"))
+	}
+
+	return key, nil
+}
+
+func sortObjectIDs(ids []primitive.ObjectID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+}
@@ -0,0 +1,84 @@
+package yamgo
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const EJSONContentType = "application/ejson"
+
+// ToEJSON encodes doc as canonical Extended JSON, preserving every BSON type.
+func ToEJSON(doc interface{}) ([]byte, error) {
+	return bson.MarshalExtJSON(doc, true, false)
+}
+
+// ToRelaxedEJSON encodes doc as relaxed Extended JSON, using plain JSON numbers and ISO dates.
+func ToRelaxedEJSON(doc interface{}) ([]byte, error) {
+	return bson.MarshalExtJSON(doc, false, false)
+}
+
+// FromEJSON decodes canonical or relaxed Extended JSON into result.
+func FromEJSON(data []byte, result interface{}) error {
+	return bson.UnmarshalExtJSON(data, false, result)
+}
+
+type ejsonResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *ejsonResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *ejsonResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// EJSONMiddleware re-encodes the JSON response bodies of next as canonical Extended JSON
+// when the request Accept header includes application/ejson.
+// Bodies that are not valid JSON documents or arrays are sent unchanged.
+func EJSONMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept"), EJSONContentType) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rw := &ejsonResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		body := rw.body.Bytes()
+		if converted, err := toCanonicalEJSON(body); err == nil {
+			body = converted
+			w.Header().Set("Content-Type", EJSONContentType)
+			w.Header().Del("Content-Length")
+		}
+
+		w.WriteHeader(rw.status)
+		_, _ = w.Write(body)
+	})
+}
+
+func toCanonicalEJSON(body []byte) ([]byte, error) {
+	// wrap the body so that top level arrays can be decoded too
+	var wrapper struct {
+		V interface{} `bson:"v"`
+	}
+
+	data := append(append([]byte(`{"v":`), body...), '}')
+	if err := bson.UnmarshalExtJSON(data, false, &wrapper); err != nil {
+		return nil, err
+	}
+
+	out, err := ToEJSON(bson.D{{Key: "v", Value: wrapper.V}})
+	if err != nil {
+		return nil, err
+	}
+
+	return out[len(`{"v":`) : len(out)-1], nil
+}
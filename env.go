@@ -0,0 +1,124 @@
+package yamgo
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewFromEnv connects using the {PREFIX}_URI, {PREFIX}_DATABASE, {PREFIX}_MAX_POOL_SIZE and
+// {PREFIX}_CONNECT_TIMEOUT_MS environment variables and returns a model bound to {PREFIX}_COLLECTION.
+// Without {PREFIX}_URI the connection string is built from {PREFIX}_HOST, {PREFIX}_PORT,
+// {PREFIX}_USERNAME and {PREFIX}_PASSWORD. As with Connect, an existing connection is reused, but an error
+// is returned if its hosts, database, username, pool size or connect timeout differ from the environment ones.
+func NewFromEnv(envPrefix string, opts ...Option) (Model, error) {
+	env := func(name string) string {
+		if envPrefix == "" {
+			return os.Getenv(name)
+		}
+		return os.Getenv(envPrefix + "_" + name)
+	}
+
+	uri := env("URI")
+	if uri == "" {
+		host := env("HOST")
+		if host == "" {
+			return Model{}, fmt.Errorf("%s_URI or %s_HOST must be set", envPrefix, envPrefix)
+		}
+		if port := env("PORT"); port != "" {
+			host += ":" + port
+		}
+
+		builder := NewURIBuilder().Host(host)
+		if user := env("USERNAME"); user != "" {
+			builder.Credential(user, env("PASSWORD"))
+		}
+
+		var err error
+		if uri, err = builder.Build(); err != nil {
+			return Model{}, err
+		}
+	}
+
+	database := env("DATABASE")
+	if database == "" {
+		return Model{}, fmt.Errorf("%s_DATABASE must be set", envPrefix)
+	}
+
+	collection := env("COLLECTION")
+	if collection == "" {
+		return Model{}, fmt.Errorf("%s_COLLECTION must be set", envPrefix)
+	}
+
+	var clientOpts []ClientOption
+
+	if v := env("MAX_POOL_SIZE"); v != "" {
+		size, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return Model{}, fmt.Errorf("invalid %s_MAX_POOL_SIZE: %w", envPrefix, err)
+		}
+		clientOpts = append(clientOpts, func(o *options.ClientOptions) error {
+			o.SetMaxPoolSize(size)
+			return nil
+		})
+	}
+
+	if v := env("CONNECT_TIMEOUT_MS"); v != "" {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || ms <= 0 {
+			return Model{}, fmt.Errorf("invalid %s_CONNECT_TIMEOUT_MS: %s", envPrefix, v)
+		}
+		clientOpts = append(clientOpts, func(o *options.ClientOptions) error {
+			o.SetConnectTimeout(time.Duration(ms) * time.Millisecond)
+			return nil
+		})
+	}
+
+	if _mongo.client != nil {
+		clientOptions, err := newClientOptions(uri, clientOpts...)
+		if err != nil {
+			return Model{}, err
+		}
+		if err := checkReusable(clientOptions, database); err != nil {
+			return Model{}, err
+		}
+	}
+
+	if err := connect(ConnectionParams{ConnectionUrl: uri, DbName: database}, clientOpts...); err != nil {
+		return Model{}, err
+	}
+
+	return newModel(collection, opts...)
+}
+
+// checkReusable returns an error if the existing connection doesn't match clientOptions and database,
+// since connect would silently ignore them.
+func checkReusable(clientOptions *options.ClientOptions, database string) error {
+	existing := _mongo.clientOptions
+
+	if name := _mongo.Database.Name(); name != database {
+		return fmt.Errorf("already connected to database %q, can't connect to %q", name, database)
+	}
+
+	if !slices.Equal(existing.Hosts, clientOptions.Hosts) {
+		return fmt.Errorf("already connected to %v, can't connect to %v", existing.Hosts, clientOptions.Hosts)
+	}
+
+	if clientOptions.Auth != nil && (existing.Auth == nil || existing.Auth.Username != clientOptions.Auth.Username) {
+		return fmt.Errorf("already connected with other credentials, can't connect as %q", clientOptions.Auth.Username)
+	}
+
+	if clientOptions.MaxPoolSize != nil && (existing.MaxPoolSize == nil || *existing.MaxPoolSize != *clientOptions.MaxPoolSize) {
+		return fmt.Errorf("already connected with another max pool size, can't use %d", *clientOptions.MaxPoolSize)
+	}
+
+	if clientOptions.ConnectTimeout != nil && (existing.ConnectTimeout == nil || *existing.ConnectTimeout != *clientOptions.ConnectTimeout) {
+		return fmt.Errorf("already connected with another connect timeout, can't use %s", *clientOptions.ConnectTimeout)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package yamgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// YamgoError adds the collection and the operation that failed to an error.
+// It unwraps to Cause, so errors.Is and errors.As see through it.
+type YamgoError struct {
+	Collection string
+	Operation  string
+	// Filter is the sanitized filter of the operation, only set with WithErrorEnrichment.
+	Filter bson.M
+	Cause  error
+}
+
+func (e *YamgoError) Error() string {
+	if e.Filter != nil {
+		return fmt.Sprintf("yamgo [%s.%s] (filter: %s): %s", e.Collection, e.Operation, formatFilter(e.Filter), e.Cause)
+	}
+	return fmt.Sprintf("yamgo [%s.%s]: %s", e.Collection, e.Operation, e.Cause)
+}
+
+func (e *YamgoError) Unwrap() error {
+	return e.Cause
+}
+
+// WrapError wraps err in a YamgoError naming the model collection and op, nil stays nil.
+// The errors returned by the driver to the model methods are wrapped this way.
+// Server selection failures become a ServerUnavailableError, so they match ErrServerUnavailable.
+func (mf *Model) WrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &YamgoError{Collection: mf.col.Name(), Operation: op, Cause: serverUnavailable(err, time.Time{})}
+}
+
+// WithErrorEnrichment adds the filter of the failed operation to the errors wrapped in a YamgoError.
+// The filter goes through the filter sanitizer if one is set, otherwise all its values are replaced with "...".
+func WithErrorEnrichment() Option {
+	return func(m *Model) error {
+		m.errorEnrichment = true
+		return nil
+	}
+}
+
+// wrapQueryError is WrapError for the operations running filter.
+func (mf *Model) wrapQueryError(op string, filter bson.M, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	wrapped := &YamgoError{Collection: mf.col.Name(), Operation: op, Cause: serverUnavailable(err, time.Time{})}
+	if mf.errorEnrichment {
+		wrapped.Filter = mf.enrichmentFilter(filter)
+	}
+
+	return wrapped
+}
+
+const maskedValue = "..."
+
+func (mf *Model) enrichmentFilter(filter bson.M) bson.M {
+	if mf.filterSanitizer != nil {
+		if sanitized := mf.filterSanitizer(filter); sanitized != nil {
+			return sanitized
+		}
+		return bson.M{}
+	}
+
+	masked := make(bson.M, len(filter))
+	for key := range filter {
+		masked[key] = maskedValue
+	}
+	return masked
+}
+
+// formatFilter formats filter with sorted keys, e.g. {email: ***, status: active}.
+func formatFilter(filter bson.M) string {
+	keys := make([]string, 0, len(filter))
+	for key := range filter {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		value := filter[key]
+		if doc, ok := value.(bson.M); ok {
+			fields[i] = key + ": " + formatFilter(doc)
+		} else {
+			fields[i] = fmt.Sprintf("%s: %v", key, value)
+		}
+	}
+
+	return "{" + strings.Join(fields, ", ") + "}"
+}
@@ -0,0 +1,148 @@
+package yamgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventStore stores the events of event-sourced aggregates, numbered per aggregate from 1.
+// Sequence numbers and snapshots are kept in the "<collection>_sequences" and "<collection>_snapshots" collections.
+type EventStore struct {
+	events    Model
+	sequences Model
+	snapshots Model
+}
+
+type storedEvent struct {
+	AggregateID string      `bson:"aggregateId"`
+	Seq         int64       `bson:"seq"`
+	Event       interface{} `bson:"event"`
+	CreatedAt   time.Time   `bson:"createdAt"`
+}
+
+type storedSnapshot struct {
+	Seq   int64    `bson:"seq"`
+	State bson.Raw `bson:"state"`
+}
+
+// NewEventStore returns an event store for the collection collectionName.
+// A unique index on the aggregate ID and sequence number is created.
+func NewEventStore(collectionName string) (EventStore, error) {
+	var store EventStore
+	var err error
+
+	if store.events, err = newModel(collectionName); err != nil {
+		return EventStore{}, err
+	}
+	if store.sequences, err = newModel(collectionName + "_sequences"); err != nil {
+		return EventStore{}, err
+	}
+	if store.snapshots, err = newModel(collectionName + "_snapshots"); err != nil {
+		return EventStore{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), MediumTimeout*time.Second)
+	defer cancel()
+
+	_, err = store.events.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "aggregateId", Value: 1}, {Key: "seq", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	if err != nil {
+		return EventStore{}, err
+	}
+
+	return store, nil
+}
+
+// AppendEvent stores event as the next event of the aggregate and returns its sequence number.
+// A failed insert leaves a gap in the sequence.
+func (es *EventStore) AppendEvent(ctx context.Context, aggregateID string, event interface{}) (int64, error) {
+	seq, err := es.sequences.FindOneAndIncrement(ctx, bson.M{"_id": aggregateID}, "seq", 1)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+
+	_, err = es.events.col.InsertOne(ctx, storedEvent{
+		AggregateID: aggregateID,
+		Seq:         seq,
+		Event:       event,
+		CreatedAt:   time.Now(),
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+// ReplayEvents calls fn with the events of the aggregate from fromSeq onwards, in sequence order.
+// Iteration stops at the first error returned by fn.
+func (es *EventStore) ReplayEvents(ctx context.Context, aggregateID string, fromSeq int64, fn func(seq int64, eventRaw bson.Raw) error) error {
+	filter := bson.M{"aggregateId": aggregateID, "seq": bson.M{"$gte": fromSeq}}
+
+	defer es.events.logSlowQuery("ReplayEvents", filter, time.Now())
+
+	cur, err := es.events.col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		seq, ok := cur.Current.Lookup("seq").AsInt64OK()
+		if !ok {
+			return fmt.Errorf("event %v has no sequence number", cur.Current.Lookup("_id"))
+		}
+
+		eventRaw, _ := cur.Current.Lookup("event").DocumentOK()
+
+		if err = fn(seq, eventRaw); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
+
+// SnapshotState stores state as the state of the aggregate after the event atSeq, replacing the previous snapshot.
+func (es *EventStore) SnapshotState(ctx context.Context, aggregateID string, state interface{}, atSeq int64) error {
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+
+	_, err := es.snapshots.col.ReplaceOne(ctx,
+		bson.M{"_id": aggregateID},
+		bson.M{"seq": atSeq, "state": state},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// LoadSnapshot decodes the last snapshot of the aggregate into state and returns its sequence number,
+// replay the events from the next one to rebuild the current state.
+// It returns mongo.ErrNoDocuments when the aggregate has no snapshot.
+func (es *EventStore) LoadSnapshot(ctx context.Context, aggregateID string, state interface{}) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+
+	var snapshot storedSnapshot
+	if err := es.snapshots.col.FindOne(ctx, bson.M{"_id": aggregateID}).Decode(&snapshot); err != nil {
+		return 0, err
+	}
+
+	if err := bson.UnmarshalWithRegistry(es.snapshots.bsonRegistry(), snapshot.State, state); err != nil {
+		return 0, err
+	}
+
+	return snapshot.Seq, nil
+}
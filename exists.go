@@ -0,0 +1,73 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultMaxConcurrency = 8
+
+// WithMaxConcurrency limits the number of queries a single operation, like ExistsAll, runs in parallel.
+func WithMaxConcurrency(n int) Option {
+	return func(m *Model) error {
+		if n <= 0 {
+			return errors.New("max concurrency must be greater than zero")
+		}
+		m.maxConcurrency = n
+		return nil
+	}
+}
+
+// Exists reports whether at least one document matches filter, without decoding it.
+func (mf *Model) Exists(ctx context.Context, filter bson.M) (bool, error) {
+	filter = mf.interceptQuery(filter)
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("Exists", filter, time.Now())
+
+	count, err := mf.col.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// ExistsAll runs Exists concurrently for every filter and returns the results in the same order.
+// The queries run one at a time when ctx carries a session.
+func (mf *Model) ExistsAll(ctx context.Context, filters []bson.M) ([]bool, error) {
+
+	results := make([]bool, len(filters))
+
+	limit := mf.maxConcurrency
+	if limit == 0 {
+		limit = defaultMaxConcurrency
+	}
+	if hasSession(ctx) {
+		limit = 1
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(limit)
+
+	for i, filter := range filters {
+		i, filter := i, filter
+		g.Go(func() error {
+			exists, err := mf.Exists(ctx, filter)
+			results[i] = exists
+			return err
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package yamgo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ExprBuilder builds an aggregation expression for $expr queries, the conditions added are combined with $and.
+// Cond, IfNull and Switch return expressions directly, e.g. to compute a field in a $project stage.
+type ExprBuilder struct {
+	conditions bson.A
+}
+
+// Expr starts an expression, e.g. Expr().Gt("$score", "$threshold").Build().
+func Expr() *ExprBuilder {
+	return &ExprBuilder{}
+}
+
+func (b *ExprBuilder) compare(op string, a, c interface{}) *ExprBuilder {
+	b.conditions = append(b.conditions, bson.M{op: bson.A{a, c}})
+	return b
+}
+
+func (b *ExprBuilder) Eq(a, c interface{}) *ExprBuilder  { return b.compare("$eq", a, c) }
+func (b *ExprBuilder) Ne(a, c interface{}) *ExprBuilder  { return b.compare("$ne", a, c) }
+func (b *ExprBuilder) Gt(a, c interface{}) *ExprBuilder  { return b.compare("$gt", a, c) }
+func (b *ExprBuilder) Gte(a, c interface{}) *ExprBuilder { return b.compare("$gte", a, c) }
+func (b *ExprBuilder) Lt(a, c interface{}) *ExprBuilder  { return b.compare("$lt", a, c) }
+func (b *ExprBuilder) Lte(a, c interface{}) *ExprBuilder { return b.compare("$lte", a, c) }
+
+// Build returns the expression, to pass to FindWithExpr or to use as the value of $expr.
+func (b *ExprBuilder) Build() interface{} {
+	switch len(b.conditions) {
+	case 0:
+		return true
+	case 1:
+		return b.conditions[0]
+	default:
+		return bson.M{"$and": b.conditions}
+	}
+}
+
+// ExprBranch is a case of Switch: Then is the value when Case is true.
+type ExprBranch struct {
+	Case interface{}
+	Then interface{}
+}
+
+// Cond returns a $cond expression evaluating to trueValue when condition is true, to falseValue otherwise.
+func (b *ExprBuilder) Cond(condition, trueValue, falseValue interface{}) interface{} {
+	return bson.M{"$cond": bson.A{condition, trueValue, falseValue}}
+}
+
+// IfNull returns an $ifNull expression evaluating to replacement when expr is null or missing.
+func (b *ExprBuilder) IfNull(expr, replacement interface{}) interface{} {
+	return bson.M{"$ifNull": bson.A{expr, replacement}}
+}
+
+// Switch returns a $switch expression evaluating to the Then of the first branch whose Case is true, to default_ if none is.
+func (b *ExprBuilder) Switch(branches []ExprBranch, default_ interface{}) interface{} {
+	cases := make(bson.A, len(branches))
+	for i, branch := range branches {
+		cases[i] = bson.M{"case": branch.Case, "then": branch.Then}
+	}
+	return bson.M{"$switch": bson.M{"branches": cases, "default": default_}}
+}
+
+// FindWithExpr finds the documents for which the aggregation expression expr is true.
+func (mf *Model) FindWithExpr(ctx context.Context, expr interface{}, results interface{}) error {
+	return mf.find(ctx, bson.M{"$expr": expr}, results)
+}
+
+// FindOneWithExpr finds a document for which the aggregation expression expr is true.
+func (mf *Model) FindOneWithExpr(ctx context.Context, expr interface{}, result interface{}) error {
+	return mf.findOne(ctx, bson.M{"$expr": expr}, result)
+}
@@ -0,0 +1,224 @@
+package yamgo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	contentHashField = "contentHash"
+	refCountField    = "refCount"
+)
+
+// YamgoFileStore stores files in a GridFS bucket, keeping a single copy of identical contents.
+type YamgoFileStore struct {
+	db         *mongo.Database
+	bucketName string
+}
+
+type FileRef struct {
+	ID          primitive.ObjectID
+	Filename    string
+	ContentHash string
+	SizeBytes   int64
+	CreatedAt   time.Time
+}
+
+type gridFSFile struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	Filename   string             `bson:"filename"`
+	Length     int64              `bson:"length"`
+	UploadDate time.Time          `bson:"uploadDate"`
+	Metadata   bson.M             `bson:"metadata"`
+}
+
+func (f gridFSFile) ref() FileRef {
+	hash, _ := f.Metadata[contentHashField].(string)
+	return FileRef{
+		ID:          f.ID,
+		Filename:    f.Filename,
+		ContentHash: hash,
+		SizeBytes:   f.Length,
+		CreatedAt:   f.UploadDate,
+	}
+}
+
+// NewFileStore returns a file store using the GridFS bucket bucketName, "fs" when empty.
+// It creates the unique index on the content hash of the bucket files, which StoreFile relies on.
+func NewFileStore(ctx context.Context, bucketName string) (YamgoFileStore, error) {
+	if bucketName == "" {
+		bucketName = options.DefaultName
+	}
+	fs := YamgoFileStore{db: _mongo.Database, bucketName: bucketName}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+
+	_, err := fs.db.Collection(bucketName+".files").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "metadata." + contentHashField, Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"metadata." + contentHashField: bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return YamgoFileStore{}, err
+	}
+
+	return fs, nil
+}
+
+// bucket returns a bucket whose operations expire with ctx.
+func (fs *YamgoFileStore) bucket(ctx context.Context) (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(fs.db, options.GridFSBucket().SetName(fs.bucketName))
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = bucket.SetWriteDeadline(deadline); err != nil {
+			return nil, err
+		}
+		if err = bucket.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	return bucket, nil
+}
+
+// StoreFile uploads the content of r unless a file with the same SHA-256 already exists,
+// in which case the existing file is referenced once more and returned. The existing file keeps
+// its filename and metadata, so filename and metadata are only stored by the first upload of a content.
+// The content is buffered in a temporary file to be hashed before the upload.
+func (fs *YamgoFileStore) StoreFile(ctx context.Context, r io.Reader, filename string, metadata bson.M) (FileRef, error) {
+
+	tmp, err := os.CreateTemp("", "yamgo-file-*")
+	if err != nil {
+		return FileRef{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tmp, hash), r); err != nil {
+		return FileRef{}, err
+	}
+	contentHash := hex.EncodeToString(hash.Sum(nil))
+
+	bucket, err := fs.bucket(ctx)
+	if err != nil {
+		return FileRef{}, err
+	}
+
+	fileMetadata := bson.M{}
+	for key, value := range metadata {
+		fileMetadata[key] = value
+	}
+	fileMetadata[contentHashField] = contentHash
+	fileMetadata[refCountField] = 1
+
+	for {
+		var existing gridFSFile
+		err = bucket.GetFilesCollection().FindOneAndUpdate(ctx,
+			bson.M{"metadata." + contentHashField: contentHash},
+			bson.M{"$inc": bson.M{"metadata." + refCountField: 1}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&existing)
+
+		if err == nil {
+			return existing.ref(), nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return FileRef{}, err
+		}
+
+		if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+			return FileRef{}, err
+		}
+
+		id := primitive.NewObjectID()
+		err = bucket.UploadFromStreamWithID(id, filename, tmp, options.GridFSUpload().SetMetadata(fileMetadata))
+		if mongo.IsDuplicateKeyError(err) {
+			// a concurrent StoreFile uploaded the same content first, drop the chunks and reference its file
+			if _, err = bucket.GetChunksCollection().DeleteMany(ctx, bson.M{"files_id": id}); err != nil {
+				return FileRef{}, err
+			}
+			continue
+		}
+		if err != nil {
+			return FileRef{}, err
+		}
+
+		var stored gridFSFile
+		if err = bucket.GetFilesCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&stored); err != nil {
+			return FileRef{}, err
+		}
+
+		return stored.ref(), nil
+	}
+}
+
+// LoadFile writes the content of the file to w.
+func (fs *YamgoFileStore) LoadFile(ctx context.Context, ref FileRef, w io.Writer) error {
+
+	bucket, err := fs.bucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = bucket.DownloadToStream(ref.ID, w)
+	return err
+}
+
+// DeleteFile releases a reference to the file, removing it once it is no longer referenced.
+func (fs *YamgoFileStore) DeleteFile(ctx context.Context, ref FileRef) error {
+
+	bucket, err := fs.bucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	files := bucket.GetFilesCollection()
+
+	for {
+		res, err := files.UpdateOne(ctx,
+			bson.M{"_id": ref.ID, "metadata." + refCountField: bson.M{"$gt": 1}},
+			bson.M{"$inc": bson.M{"metadata." + refCountField: -1}},
+		)
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount > 0 {
+			return nil
+		}
+
+		// the refCount condition keeps a file referenced again by StoreFile in the meantime
+		deleted, err := files.DeleteOne(ctx, bson.M{"_id": ref.ID, "metadata." + refCountField: bson.M{"$lte": 1}})
+		if err != nil {
+			return err
+		}
+		if deleted.DeletedCount > 0 {
+			_, err = bucket.GetChunksCollection().DeleteMany(ctx, bson.M{"files_id": ref.ID})
+			return err
+		}
+
+		count, err := files.CountDocuments(ctx, bson.M{"_id": ref.ID})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return gridfs.ErrFileNotFound
+		}
+	}
+}
@@ -14,26 +14,72 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Cursor iterates over the documents returned by a streaming query.
+type Cursor = mongo.Cursor
+
 type PopulateOptions struct {
 	Collection string
+	// LocalField holds the _id, or array of _id, of the documents of Collection to populate.
 	LocalField string
+	// As is the field receiving the populated documents, LocalField itself when empty.
 	As         string
 	Projection []string
 }
 
-func (mf *Model) FindOne(filter bson.M, result interface{}) (err error) {
+func (mf *Model) FindOne(filter bson.M, result interface{}, opts ...FindOption) (err error) {
+	return mf.findOne(context.Background(), filter, result, opts...)
+}
+
+// FindOneContext is the context aware equivalent of FindOne.
+func (mf *Model) FindOneContext(ctx context.Context, filter bson.M, result interface{}, opts ...FindOption) error {
+	return mf.findOne(ctx, filter, result, opts...)
+}
+
+func (mf *Model) findOne(ctx context.Context, filter bson.M, result interface{}, opts ...FindOption) (err error) {
+	filter = mf.interceptQuery(filter)
+
+	o, err := applyFindOptions(opts)
+	if err != nil {
+		return err
+	}
+	filter = mf.scopeDeleted(filter, o.includeDeleted)
+
+	col, err := mf.readCollection(o)
+	if err != nil {
+		return err
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), MediumTimeout*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
 
 	defer cancel()
+	defer mf.logSlowQuery("FindOne", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = 1
+		}
+		mf.observe("FindOne", filter, nil, count, start, err)
+	}(time.Now())
+
+	findOneOptions := options.FindOne()
+	if projection := o.projectionWithScore(); projection != nil {
+		findOneOptions.SetProjection(projection)
+	}
+	if o.textScore {
+		findOneOptions.SetSort(bson.D{{Key: TextScoreField, Value: textScoreMeta}})
+	}
+	if o.comment != "" {
+		findOneOptions.SetComment(o.comment)
+	}
 
-	res := mf.col.FindOne(ctx, filter)
+	res := col.FindOne(ctx, filter, findOneOptions)
+	mf.checkIndexUsage("FindOne", filter)
 
 	if res.Err() != nil {
-		return res.Err()
+		return mf.wrapQueryError("FindOne", filter, res.Err())
 	}
 
-	err = res.Decode(result)
+	err = mf.decodeSingleResult(res, result)
 
 	if err != nil {
 		return err
@@ -42,42 +88,376 @@ func (mf *Model) FindOne(filter bson.M, result interface{}) (err error) {
 	return nil
 }
 
-func (mf *Model) FindByID(id string, result interface{}) (err error) {
+// FindOneMap finds a document matching filter and returns it as a map of plain Go values that encoding/json
+// can marshal: ObjectIDs become hex strings, dates and timestamps time.Time, decimals strings, binaries []byte,
+// and nested documents and arrays maps and slices.
+func (mf *Model) FindOneMap(ctx context.Context, filter bson.M) (map[string]interface{}, error) {
+	var doc bson.M
+	if err := mf.findOne(ctx, filter, &doc); err != nil {
+		return nil, err
+	}
+
+	return toGoValue(doc).(map[string]interface{}), nil
+}
+
+func toGoValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.M:
+		m := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			m[key] = toGoValue(elem)
+		}
+		return m
+	case bson.D:
+		m := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			m[e.Key] = toGoValue(e.Value)
+		}
+		return m
+	case bson.A:
+		a := make([]interface{}, len(v))
+		for i, elem := range v {
+			a[i] = toGoValue(elem)
+		}
+		return a
+	case primitive.ObjectID:
+		return v.Hex()
+	case primitive.DateTime:
+		return v.Time()
+	case primitive.Timestamp:
+		return time.Unix(int64(v.T), 0)
+	case primitive.Decimal128:
+		return v.String()
+	case primitive.Binary:
+		return v.Data
+	case primitive.Regex:
+		return v.Pattern
+	case primitive.Symbol:
+		return string(v)
+	case primitive.JavaScript:
+		return string(v)
+	case primitive.Null, primitive.Undefined, primitive.MinKey, primitive.MaxKey:
+		return nil
+	default:
+		return v
+	}
+}
+
+func (mf *Model) FindByID(id string, result interface{}, opts ...FindOption) (err error) {
+	return mf.FindByIDContext(context.Background(), id, result, opts...)
+}
+
+// FindByIDContext is the context aware equivalent of FindByID.
+func (mf *Model) FindByIDContext(ctx context.Context, id string, result interface{}, opts ...FindOption) (err error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 
 	if err != nil {
 		return err
 	}
 
-	return mf.FindOne(bson.M{"_id": objectID}, result)
+	return mf.findOne(ctx, bson.M{"_id": objectID}, result, opts...)
 }
 
-func (mf *Model) FindByObjectID(objectID primitive.ObjectID, result interface{}) (err error) {
+func (mf *Model) FindByObjectID(objectID primitive.ObjectID, result interface{}, opts ...FindOption) (err error) {
+	return mf.FindByObjectIDContext(context.Background(), objectID, result, opts...)
+}
+
+// FindByObjectIDContext is the context aware equivalent of FindByObjectID.
+func (mf *Model) FindByObjectIDContext(ctx context.Context, objectID primitive.ObjectID, result interface{}, opts ...FindOption) (err error) {
+	return mf.findOne(ctx, bson.M{"_id": objectID}, result, opts...)
+}
+
+// FindByField finds the documents whose field equals value.
+func (mf *Model) FindByField(ctx context.Context, field string, value interface{}, results interface{}) error {
+	return mf.find(ctx, bson.M{field: value}, results)
+}
+
+// FindOneByField finds a document whose field equals value.
+func (mf *Model) FindOneByField(ctx context.Context, field string, value interface{}, result interface{}) error {
+	return mf.findOne(ctx, bson.M{field: value}, result)
+}
+
+func (mf *Model) Find(filter bson.M, results interface{}, opts ...FindOption) error {
+	return mf.find(context.Background(), filter, results, opts...)
+}
+
+// FindContext is the context aware equivalent of Find.
+func (mf *Model) FindContext(ctx context.Context, filter bson.M, results interface{}, opts ...FindOption) error {
+	return mf.find(ctx, filter, results, opts...)
+}
+
+func (mf *Model) find(ctx context.Context, filter bson.M, results interface{}, opts ...FindOption) (err error) {
+	filter = mf.interceptQuery(filter)
+
+	o, err := applyFindOptions(opts)
+	if err != nil {
+		return err
+	}
+	filter = mf.scopeDeleted(filter, o.includeDeleted)
+
+	col, err := mf.readCollection(o)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("Find", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = resultsLen(results)
+		}
+		mf.observe("Find", filter, nil, count, start, err)
+	}(time.Now())
 
+	findOptions := options.Find()
+	if projection := o.projectionWithScore(); projection != nil {
+		findOptions.SetProjection(projection)
+	}
+	if o.textScore {
+		findOptions.SetSort(bson.D{{Key: TextScoreField, Value: textScoreMeta}})
+	}
+	if o.comment != "" {
+		findOptions.SetComment(o.comment)
+	}
+
+	cur, err := col.Find(ctx, filter, findOptions)
 	if err != nil {
+		return mf.wrapQueryError("Find", filter, err)
+	}
+
+	mf.checkIndexUsage("Find", filter)
+
+	if err = mf.decodeAll(ctx, cur, results); err != nil {
 		return err
 	}
 
-	return mf.FindOne(bson.M{"_id": objectID}, result)
+	return nil
 }
 
-func (mf *Model) Find(filter bson.M, results interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), LongTimeout*time.Second)
+// FindWithHint finds the documents matching filter, forcing the query planner to use hint,
+// an index name or key specification.
+func (mf *Model) FindWithHint(ctx context.Context, filter bson.M, hint interface{}, results interface{}) (err error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
 	defer cancel()
+	defer mf.logSlowQuery("FindWithHint", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = resultsLen(results)
+		}
+		mf.observe("FindWithHint", filter, nil, count, start, err)
+	}(time.Now())
 
-	cur, err := mf.col.Find(ctx, filter)
+	cur, err := mf.col.Find(ctx, filter, options.Find().SetHint(hint))
+	if err != nil {
+		return mf.wrapQueryError("FindWithHint", filter, err)
+	}
+
+	return mf.decodeAll(ctx, cur, results)
+}
+
+// FindWithValidatedHint is like FindWithHint but first checks indexName exists, returning ErrIndexNotFound
+// without running the query otherwise.
+func (mf *Model) FindWithValidatedHint(ctx context.Context, filter bson.M, indexName string, results interface{}) error {
+	exists, err := mf.indexExists(ctx, indexName)
 	if err != nil {
 		return err
 	}
 
-	if err = cur.All(ctx, results); err != nil {
+	if !exists {
+		return fmt.Errorf("%w: %s on %s", ErrIndexNotFound, indexName, mf.col.Name())
+	}
+
+	return mf.FindWithHint(ctx, filter, indexName, results)
+}
+
+// FindFirst finds the document matching filter with the smallest sortField value.
+func (mf *Model) FindFirst(ctx context.Context, filter bson.M, sortField string, result interface{}, opts ...FindOption) error {
+	return mf.findEdge(ctx, "FindFirst", filter, bson.D{{Key: sortField, Value: 1}}, result, opts)
+}
+
+// FindLast finds the document matching filter with the largest sortField value.
+func (mf *Model) FindLast(ctx context.Context, filter bson.M, sortField string, result interface{}, opts ...FindOption) error {
+	return mf.findEdge(ctx, "FindLast", filter, bson.D{{Key: sortField, Value: -1}}, result, opts)
+}
+
+func (mf *Model) findEdge(ctx context.Context, op string, filter bson.M, sort bson.D, result interface{}, opts []FindOption) error {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	o, err := applyFindOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	col, err := mf.readCollection(o)
+	if err != nil {
+		return err
+	}
+
+	findOneOptions := options.FindOne().SetSort(sort)
+	if o.projection != nil {
+		findOneOptions.SetProjection(o.projection)
+	}
+	if o.comment != "" {
+		findOneOptions.SetComment(o.comment)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery(op, filter, time.Now())
+
+	res := col.FindOne(ctx, filter, findOneOptions)
+	if res.Err() != nil {
+		return res.Err()
+	}
+
+	return mf.decodeSingleResult(res, result)
+}
+
+// FindInBatches streams the documents matching filter and calls fn with chunks of at most batchSize documents.
+// Iteration stops at the first error returned by fn.
+func (mf *Model) FindInBatches(ctx context.Context, filter bson.M, batchSize int, fn func(batch []bson.Raw, batchNum int) error) error {
+	filter = mf.interceptQuery(filter)
+
+	if batchSize <= 0 {
+		return errors.New("batch size must be greater than zero")
+	}
+
+	defer mf.logSlowQuery("FindInBatches", filter, time.Now())
+
+	cur, err := mf.col.Find(ctx, filter, options.Find().SetBatchSize(int32(batchSize)))
+	if err != nil {
 		return err
 	}
 
+	defer cur.Close(ctx)
+
+	batch := make([]bson.Raw, 0, batchSize)
+	batchNum := 0
+
+	for cur.Next(ctx) {
+		// cur.Current is only valid until the next call to Next
+		doc := make(bson.Raw, len(cur.Current))
+		copy(doc, cur.Current)
+
+		doc, err = mf.interceptResult(doc)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, doc)
+
+		if len(batch) == batchSize {
+			if err = fn(batch, batchNum); err != nil {
+				return err
+			}
+			batch = make([]bson.Raw, 0, batchSize)
+			batchNum++
+		}
+	}
+
+	if err = cur.Err(); err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		return fn(batch, batchNum)
+	}
+
 	return nil
 }
 
-func (mf *Model) executeCursorQuery(query []bson.M, sort bson.D, limit int64, collation *options.Collation, hint interface{}, projection string, lookups []PopulateOptions, results interface{}) error {
+// FindInBatchesT works like FindInBatches but decodes every batch into a []T.
+func FindInBatchesT[T any](ctx context.Context, mf *Model, filter bson.M, batchSize int, fn func(batch []T, batchNum int) error) error {
+	return mf.FindInBatches(ctx, filter, batchSize, func(raws []bson.Raw, batchNum int) error {
+		batch := make([]T, len(raws))
+		for i, raw := range raws {
+			if err := bson.Unmarshal(raw, &batch[i]); err != nil {
+				return err
+			}
+		}
+		return fn(batch, batchNum)
+	})
+}
+
+// FindAndGroupBy finds the documents matching filter and groups them by the key keyFn returns for each.
+// The grouping happens in the application, use an aggregation $group for large result sets.
+func FindAndGroupBy[K comparable, V any](ctx context.Context, mf *Model, filter bson.M, keyFn func(V) K) (map[K][]V, error) {
+	var docs []V
+	if err := mf.find(ctx, filter, &docs); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[K][]V)
+	for _, doc := range docs {
+		key := keyFn(doc)
+		groups[key] = append(groups[key], doc)
+	}
+
+	return groups, nil
+}
+
+// FindPipe finds the documents matching filter and returns transform applied to each of them, decoding them one at a time.
+// It stops at the first error returned by transform.
+func FindPipe[T, R any](ctx context.Context, mf *Model, filter bson.M, transform func(T) (R, error)) ([]R, error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindPipe", filter, time.Now())
+
+	cur, err := mf.col.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	results := []R{}
+	for cur.Next(ctx) {
+		var doc T
+		if err = mf.decodeRaw(cur.Current, &doc); err != nil {
+			return nil, err
+		}
+
+		result, err := transform(doc)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (mf *Model) executeCursorQuery(ctx context.Context, query []bson.M, sort bson.D, limit int64, collation *options.Collation, hint interface{}, projection string, comment string, lookups []PopulateOptions, results interface{}) error {
+
+	options, err := cursorQueryOptions(sort, limit, collation, hint, projection, comment)
+	if err != nil {
+		return err
+	}
+
+	return mf.findAndPopulate(ctx, bson.M{"$and": query}, *options, lookups, results)
+}
+
+// cursorQueryOptions returns the options of the page query of PaginatedFind, fetching one more document
+// than limit to know whether there is a next page.
+func cursorQueryOptions(sort bson.D, limit int64, collation *options.Collation, hint interface{}, projection string, comment string) (*options.FindOptions, error) {
 
 	options := options.Find()
 	options.SetSort(sort)
@@ -92,21 +472,90 @@ func (mf *Model) executeCursorQuery(query []bson.M, sort bson.D, limit int64, co
 	}
 
 	if projection != "" {
-		pMap := make(map[string]bool)
-		str := strings.ReplaceAll(projection, "id", "_id")
-		for _, key := range strings.Split(str, ",") {
-			pMap[key] = true
+		p, err := ParseProjection(projection)
+		if err != nil {
+			return nil, err
 		}
-		options.SetProjection(pMap)
+		options.SetProjection(p)
+	}
+
+	if comment != "" {
+		options.SetComment(comment)
+	}
+
+	return options, nil
+}
+
+// ExplainPaginatedFind returns the query planner output of the aggregation PaginatedFind would run for params,
+// including the $lookup stages of params.Expansion.
+func (mf *Model) ExplainPaginatedFind(ctx context.Context, params PaginationFindParams) (bson.M, error) {
+
+	params = ensureMandatoryParams(params)
+	params.Query = mf.scopeDeleted(params.Query, params.IncludeDeleted)
+
+	var pipeline mongo.Pipeline
+	var aggregateOptions *options.AggregateOptions
+	var err error
+	if params.TextScoreSort {
+		params.Query = mf.interceptQuery(params.Query)
+		if pipeline, err = mf.textScorePagePipeline(params); err != nil {
+			return nil, err
+		}
+		aggregateOptions = pageAggregateOptions(params)
+	} else {
+		queries, sort, err := BuildQueries(params)
+		if err != nil {
+			return nil, err
+		}
+
+		findOptions, err := cursorQueryOptions(sort, params.Limit, params.Collation, params.Hint, params.Projection, params.Comment)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline, aggregateOptions, _ = mf.populatePipeline(mf.interceptQuery(bson.M{"$and": queries}), *findOptions, params.Expansion)
+	}
+
+	aggregate := bson.D{
+		{Key: "aggregate", Value: mf.col.Name()},
+		{Key: "pipeline", Value: pipeline},
+		{Key: "cursor", Value: bson.M{}},
+	}
+
+	if aggregateOptions.Collation != nil {
+		aggregate = append(aggregate, bson.E{Key: "collation", Value: aggregateOptions.Collation.ToDocument()})
 	}
 
-	return mf.FindAndPopulate(bson.M{"$and": query}, *options, lookups, results)
+	if aggregateOptions.Hint != nil {
+		aggregate = append(aggregate, bson.E{Key: "hint", Value: aggregateOptions.Hint})
+	}
 
-	// return mf.FindWithOptions(bson.M{"$and": query}, *options, results)
+	if aggregateOptions.Comment != nil {
+		aggregate = append(aggregate, bson.E{Key: "comment", Value: aggregateOptions.Comment})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
 
+	var explain bson.M
+	err = mf.col.Database().RunCommand(ctx, bson.D{
+		{Key: "explain", Value: aggregate},
+		{Key: "verbosity", Value: "queryPlanner"},
+	}).Decode(&explain)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return explain, nil
 }
 
 func (mf *Model) PaginatedFind(params PaginationFindParams, results interface{}) (Page, error) {
+	return mf.PaginatedFindContext(context.Background(), params, results)
+}
+
+// PaginatedFindContext is the context aware equivalent of PaginatedFind.
+func (mf *Model) PaginatedFindContext(ctx context.Context, params PaginationFindParams, results interface{}) (Page, error) {
 
 	var err error
 
@@ -115,27 +564,218 @@ func (mf *Model) PaginatedFind(params PaginationFindParams, results interface{})
 	}
 
 	params = ensureMandatoryParams(params)
-	shouldSecondarySortOnID := params.PaginatedField != "_id"
+	params.Query = mf.scopeDeleted(params.Query, params.IncludeDeleted)
 
 	var count int
 	if params.CountTotal {
-		count, err = mf.CountDocuments(params.Query)
+		total, err := mf.Count(ctx, params.Query, IncludeDeleted())
+		if err != nil {
+			return Page{}, err
+		}
+		count = int(total)
+	}
+
+	if params.TextScoreSort {
+		err = mf.findTextScorePage(ctx, params, results)
+	} else {
+		var queries []bson.M
+		var sort bson.D
+		queries, sort, err = BuildQueries(params)
 		if err != nil {
 			return Page{}, err
 		}
+
+		err = mf.executeCursorQuery(ctx, queries, sort, params.Limit, params.Collation, params.Hint, params.Projection, params.Comment, params.Expansion, results)
+	}
+
+	if err != nil {
+		return Page{}, err
+	}
+
+	return buildPage(params, count, results)
+}
+
+// findTextScorePage fetches the page of a PaginatedFind with TextScoreSort, and one more document to know
+// whether there is a next page, with an aggregation since the cursor matches on the score added by pageStages.
+func (mf *Model) findTextScorePage(ctx context.Context, params PaginationFindParams, results interface{}) error {
+
+	params.Query = mf.interceptQuery(params.Query)
+
+	pipeline, err := mf.textScorePagePipeline(params)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("PaginatedFind", params.Query, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline, pageAggregateOptions(params))
+	if err != nil {
+		return err
+	}
+
+	return mf.decodeAll(ctx, cur, results)
+}
+
+// textScorePagePipeline returns the aggregation fetching the page of a PaginatedFind with TextScoreSort.
+func (mf *Model) textScorePagePipeline(params PaginationFindParams) (mongo.Pipeline, error) {
+
+	pipeline, err := pageStages(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Projection != "" {
+		projection, err := ParseProjection(params.Projection)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: projection}})
+	}
+
+	return append(pipeline, BuildLookupStages(params.Expansion, !mf.separateAddFields)...), nil
+}
+
+// pageStages returns the stages matching, sorting and limiting the page of params to Limit + 1 documents.
+// With TextScoreSort the $text query comes first, as MongoDB requires, and the cursor matches once
+// the score is added to the documents in TextScoreField.
+func pageStages(params PaginationFindParams) (mongo.Pipeline, error) {
+
+	queries, cursorQuery, sort, err := buildQueries(params)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{}
+	if !params.TextScoreSort {
+		if cursorQuery != nil {
+			queries = append(queries, cursorQuery)
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$and": queries}}})
+	} else {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$match", Value: bson.M{"$and": queries}}},
+			bson.D{{Key: "$addFields", Value: bson.M{TextScoreField: textScoreMeta}}},
+		)
+		if cursorQuery != nil {
+			pipeline = append(pipeline, bson.D{{Key: "$match", Value: cursorQuery}})
+		}
 	}
 
-	queries, sort, err := BuildQueries(params)
+	return append(pipeline,
+		bson.D{{Key: "$sort", Value: sort}},
+		bson.D{{Key: "$limit", Value: params.Limit + 1}},
+	), nil
+}
 
+// pageAggregateOptions returns the options of the aggregations fetching the page of params.
+func pageAggregateOptions(params PaginationFindParams) *options.AggregateOptions {
+	aggregateOptions := options.Aggregate()
+	if params.Collation != nil {
+		aggregateOptions.SetCollation(params.Collation)
+	}
+	if params.Hint != nil {
+		aggregateOptions.SetHint(params.Hint)
+	}
+	if params.Comment != "" {
+		aggregateOptions.SetComment(params.Comment)
+	}
+	return aggregateOptions
+}
+
+// facetCountField holds the total count PaginatedFindFaceted adds to the page documents.
+const facetCountField = "_yamgoCount"
+
+// PaginatedFindFaceted works like PaginatedFind but fetches the page and, with CountTotal, the total count
+// in a single aggregation. The page query runs first so that its $match, $sort and $limit can use an index,
+// the count of params.Query is added with $unionWith and both are split by a final $facet. It requires MongoDB 4.4+.
+// The page is returned in one document, so it must fit in 16MB.
+func (mf *Model) PaginatedFindFaceted(ctx context.Context, params PaginationFindParams, results interface{}) (Page, error) {
+
+	if results == nil {
+		return Page{}, errors.New("results can't be nil")
+	}
+
+	params.Query = mf.scopeDeleted(mf.interceptQuery(params.Query), params.IncludeDeleted)
+	if params.Query == nil {
+		params.Query = bson.M{}
+	}
+	params = ensureMandatoryParams(params)
+
+	pipeline, err := pageStages(params)
 	if err != nil {
 		return Page{}, err
 	}
 
-	err = mf.executeCursorQuery(queries, sort, params.Limit, params.Collation, params.Hint, params.Projection, params.Expansion, results)
+	if params.Projection != "" {
+		projection, err := ParseProjection(params.Projection)
+		if err != nil {
+			return Page{}, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: projection}})
+	}
+	pipeline = append(pipeline, BuildLookupStages(params.Expansion, !mf.separateAddFields)...)
+
+	if params.CountTotal {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll": mf.col.Name(),
+			"pipeline": bson.A{
+				bson.D{{Key: "$match", Value: params.Query}},
+				bson.D{{Key: "$count", Value: facetCountField}},
+			},
+		}}})
+	}
 
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.D{
+		{Key: "data", Value: bson.A{bson.D{{Key: "$match", Value: bson.M{facetCountField: bson.M{"$exists": false}}}}}},
+		{Key: "count", Value: bson.A{bson.D{{Key: "$match", Value: bson.M{facetCountField: bson.M{"$exists": true}}}}}},
+	}}})
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("PaginatedFindFaceted", params.Query, time.Now())
+
+	cur, err := mf.col.Aggregate(ctx, pipeline, pageAggregateOptions(params))
 	if err != nil {
 		return Page{}, err
 	}
+	defer cur.Close(ctx)
+
+	if !cur.Next(ctx) {
+		if err = cur.Err(); err != nil {
+			return Page{}, err
+		}
+		return Page{}, errors.New("$facet returned no document")
+	}
+
+	var faceted struct {
+		Data  []bson.Raw `bson:"data"`
+		Count []struct {
+			Count int `bson:"_yamgoCount"`
+		} `bson:"count"`
+	}
+	if err = bson.Unmarshal(cur.Current, &faceted); err != nil {
+		return Page{}, err
+	}
+
+	if err = mf.decodeRaws(faceted.Data, results); err != nil {
+		return Page{}, err
+	}
+
+	var count int
+	if len(faceted.Count) > 0 {
+		count = faceted.Count[0].Count
+	}
+
+	return buildPage(params, count, results)
+}
+
+// buildPage trims the extra document fetched to detect a next page from results and computes the page cursors.
+func buildPage(params PaginationFindParams, count int, results interface{}) (Page, error) {
+
+	var err error
+	shouldSecondarySortOnID := params.PaginatedField != "_id"
 
 	resultsPtr := reflect.ValueOf(results)
 	resultsVal := resultsPtr.Elem()
@@ -161,9 +801,17 @@ func (mf *Model) PaginatedFind(params PaginationFindParams, results interface{})
 			}
 		}
 
+		var seenIDs bson.A
+		if params.UseStablePages {
+			seenIDs, err = pageIDs(resultsVal)
+			if err != nil {
+				return Page{}, err
+			}
+		}
+
 		if hasPrevious {
 			firstResult := resultsVal.Index(0).Interface()
-			previousCursor, err = generateCursor(firstResult, params.PaginatedField, shouldSecondarySortOnID)
+			previousCursor, err = generateCursor(firstResult, params.PaginatedField, shouldSecondarySortOnID, seenIDs)
 			if err != nil {
 				return Page{}, fmt.Errorf("could not create a previous cursor: %s", err)
 			}
@@ -171,7 +819,7 @@ func (mf *Model) PaginatedFind(params PaginationFindParams, results interface{})
 
 		if hasNext {
 			lastResult := resultsVal.Index(resultsVal.Len() - 1).Interface()
-			nextCursor, err = generateCursor(lastResult, params.PaginatedField, shouldSecondarySortOnID)
+			nextCursor, err = generateCursor(lastResult, params.PaginatedField, shouldSecondarySortOnID, seenIDs)
 			if err != nil {
 				return Page{}, fmt.Errorf("could not create a next cursor: %s", err)
 			}
@@ -191,39 +839,184 @@ func (mf *Model) PaginatedFind(params PaginationFindParams, results interface{})
 	return page, nil
 }
 
+// pageIDs returns the _id of every document of results.
+func pageIDs(results reflect.Value) (bson.A, error) {
+	ids := make(bson.A, results.Len())
+	for i := 0; i < results.Len(); i++ {
+		raw, err := bson.Marshal(results.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		id, err := bson.Raw(raw).LookupErr("_id")
+		if err != nil {
+			return nil, fmt.Errorf("could not read the _id of the page documents: %s", err)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
 func (mf *Model) FindWithOptions(filter bson.M, option options.FindOptions, results interface{}) error {
+	return mf.FindWithOptionsContext(context.Background(), filter, option, results)
+}
+
+// FindWithOptionsContext is the context aware equivalent of FindWithOptions.
+func (mf *Model) FindWithOptionsContext(ctx context.Context, filter bson.M, option options.FindOptions, results interface{}) error {
+	filter = mf.interceptQuery(filter)
 
-	ctx, cancel := context.WithTimeout(context.Background(), LongTimeout*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
 
 	defer cancel()
+	defer mf.logSlowQuery("FindWithOptions", filter, time.Now())
 
 	cur, err := mf.col.Find(ctx, filter, &option)
 	if err != nil {
 		return err
 	}
-	err = cur.All(ctx, results)
+	err = mf.decodeAll(ctx, cur, results)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// FindWithSkip returns at most limit documents matching filter in sort order, after skipping the first skip.
+// The server still walks the skipped documents, so the cost grows linearly with skip: use PaginatedFind for large collections.
+func (mf *Model) FindWithSkip(ctx context.Context, filter bson.M, sort bson.D, skip, limit int64, results interface{}) error {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindWithSkip", filter, time.Now())
+
+	findOptions := options.Find().SetSkip(skip).SetLimit(limit)
+	if sort != nil {
+		findOptions.SetSort(sort)
+	}
+
+	cur, err := mf.col.Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+
+	return mf.decodeAll(ctx, cur, results)
+}
+
+// FindWithNaturalOrder returns the documents matching filter in insertion order, or reversed.
+// The order is only guaranteed on capped collections.
+func (mf *Model) FindWithNaturalOrder(ctx context.Context, filter bson.M, reverse bool, results interface{}) error {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindWithNaturalOrder", filter, time.Now())
+
+	cur, err := mf.col.Find(ctx, filter, options.Find().SetSort(naturalSort(reverse)))
+	if err != nil {
+		return err
+	}
+
+	return mf.decodeAll(ctx, cur, results)
+}
+
+// TailableCursor follows a capped collection, Next blocks until a new document is inserted or ctx is done.
+type TailableCursor = Cursor
+
+// TailableFollowNatural opens a tailable await cursor on the documents of a capped collection matching filter,
+// in insertion order. The caller must close the cursor.
+func (mf *Model) TailableFollowNatural(ctx context.Context, filter bson.M) (*TailableCursor, error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	findOptions := options.Find().
+		SetCursorType(options.TailableAwait).
+		SetSort(naturalSort(false))
+
+	return mf.col.Find(ctx, filter, findOptions)
+}
+
+func naturalSort(reverse bool) bson.D {
+	if reverse {
+		return bson.D{{Key: "$natural", Value: -1}}
+	}
+	return bson.D{{Key: "$natural", Value: 1}}
+}
+
 func (mf *Model) FindOneAndPopulate(filter bson.M, findOptions options.FindOptions, populate []PopulateOptions, result interface{}) error {
+	return mf.FindOneAndPopulateContext(context.Background(), filter, findOptions, populate, result)
+}
+
+// FindOneAndPopulateContext is the context aware equivalent of FindOneAndPopulate.
+func (mf *Model) FindOneAndPopulateContext(ctx context.Context, filter bson.M, findOptions options.FindOptions, populate []PopulateOptions, result interface{}) error {
 	findOptions.SetLimit(-1)
-	return mf.FindAndPopulate(filter, findOptions, populate, result)
+	return mf.findAndPopulate(ctx, filter, findOptions, populate, result)
 }
 
 func (mf *Model) FindAndPopulate(filter bson.M, option options.FindOptions, populate []PopulateOptions, results interface{}) error {
+	return mf.findAndPopulate(context.Background(), filter, option, populate, results)
+}
+
+// FindAndPopulateContext is the context aware equivalent of FindAndPopulate.
+func (mf *Model) FindAndPopulateContext(ctx context.Context, filter bson.M, option options.FindOptions, populate []PopulateOptions, results interface{}) error {
+	return mf.findAndPopulate(ctx, filter, option, populate, results)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), LongTimeout*time.Second)
+func (mf *Model) findAndPopulate(ctx context.Context, filter bson.M, option options.FindOptions, populate []PopulateOptions, results interface{}) error {
+	filter = mf.interceptQuery(filter)
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
 
 	defer cancel()
+	defer mf.logSlowQuery("FindAndPopulate", filter, time.Now())
+
+	pipeline, aggregateOptions, single := mf.populatePipeline(filter, option, populate)
+
+	cur, err := mf.col.Aggregate(ctx, pipeline, aggregateOptions)
+
+	if err != nil {
+		return err
+	}
+
+	if single {
+		defer cur.Close(ctx)
+
+		if !cur.Next(ctx) {
+			if err := cur.Err(); err != nil {
+				return err
+			}
+			return mongo.ErrNoDocuments
+		}
+
+		return mf.decodeRaw(cur.Current, results)
+	}
+
+	if err := mf.decodeAll(ctx, cur, results); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// populatePipeline returns the aggregation FindAndPopulate runs, and whether it returns a single document.
+func (mf *Model) populatePipeline(filter bson.M, option options.FindOptions, populate []PopulateOptions) (mongo.Pipeline, *options.AggregateOptions, bool) {
 
 	var limit = 10
 
 	pipeline := mongo.Pipeline{}
 
-	if option.Limit != nil && *option.Limit > 0 {
+	// a negative limit, set by FindOneAndPopulate, returns a single document
+	single := option.Limit != nil && *option.Limit < 0
+	if single {
+		limit = 1
+	} else if option.Limit != nil && *option.Limit > 0 {
 		limit = int(*option.Limit)
 	}
 
@@ -254,28 +1047,36 @@ func (mf *Model) FindAndPopulate(filter bson.M, option options.FindOptions, popu
 		pipeline = append(pipeline, projectionStage)
 	}
 
-	for _, value := range populate {
-		pipeline = append(pipeline, BuildLookupStage(value)...)
-	}
-
-	cur, err := mf.col.Aggregate(ctx, pipeline)
+	pipeline = append(pipeline, BuildLookupStages(populate, !mf.separateAddFields)...)
 
-	if err != nil {
-		return err
+	aggregateOptions := options.Aggregate()
+	if option.Comment != nil {
+		aggregateOptions.SetComment(*option.Comment)
 	}
-
-	if err := cur.All(ctx, results); err != nil {
-		return err
+	if option.MaxTime != nil {
+		aggregateOptions.SetMaxTime(*option.MaxTime)
+	}
+	if option.Collation != nil {
+		aggregateOptions.SetCollation(option.Collation)
+	}
+	if option.Hint != nil {
+		aggregateOptions.SetHint(option.Hint)
 	}
 
-	return nil
+	return pipeline, aggregateOptions, single
 }
 
 func (mf *Model) Aggregate(pipeline mongo.Pipeline, results interface{}) error {
+	return mf.AggregateContext(context.Background(), pipeline, results)
+}
+
+// AggregateContext is the context aware equivalent of Aggregate.
+func (mf *Model) AggregateContext(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
 
-	ctx, cancel := context.WithTimeout(context.Background(), MediumTimeout*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
 
 	defer cancel()
+	defer mf.logSlowQuery("Aggregate", nil, time.Now())
 
 	cur, err := mf.col.Aggregate(ctx, pipeline)
 
@@ -292,38 +1093,112 @@ func (mf *Model) Aggregate(pipeline mongo.Pipeline, results interface{}) error {
 
 func BuildLookupStage(populate PopulateOptions) []bson.D {
 
-	lookup := bson.D{
+	lookup, field, temporary := buildLookup(populate)
+
+	expansion := []bson.D{lookup, {{Key: "$addFields", Value: bson.D{field}}}}
+
+	if temporary != "" {
+		expansion = append(expansion, bson.D{{Key: "$unset", Value: temporary}})
+	}
+
+	return expansion
+}
+
+// BuildLookupStages returns the stages populating every entry of populate. With merge, all the lookups
+// run first and are followed by a single $addFields and $unset, instead of up to three stages per entry.
+// Entries reading a field populated by a previous entry are never merged.
+func BuildLookupStages(populate []PopulateOptions, merge bool) []bson.D {
+
+	if !merge || !independentPopulations(populate) {
+		stages := []bson.D{}
+		for _, value := range populate {
+			stages = append(stages, BuildLookupStage(value)...)
+		}
+		return stages
+	}
+
+	stages := []bson.D{}
+	fields := bson.D{}
+	temporaries := bson.A{}
+	for _, value := range populate {
+		lookup, field, temporary := buildLookup(value)
+		stages = append(stages, lookup)
+		fields = append(fields, field)
+		if temporary != "" {
+			temporaries = append(temporaries, temporary)
+		}
+	}
+
+	if len(fields) > 0 {
+		stages = append(stages, bson.D{{Key: "$addFields", Value: fields}})
+	}
+	if len(temporaries) > 0 {
+		stages = append(stages, bson.D{{Key: "$unset", Value: temporaries}})
+	}
+
+	return stages
+}
+
+// buildLookup returns the $lookup stage of populate, the field setting the populated value and,
+// when populating LocalField in place, the temporary field to remove afterwards.
+func buildLookup(populate PopulateOptions) (lookup bson.D, field bson.E, temporary string) {
+
+	as := populate.As
+	if as == "" {
+		as = populate.LocalField
+	}
+
+	// populating LocalField in place, the lookup goes to a temporary field so that
+	// $isArray still sees the original LocalField value
+	lookupAs := as
+	if as == populate.LocalField {
+		lookupAs = "_populated_" + strings.ReplaceAll(as, ".", "_")
+		temporary = lookupAs
+	}
+
+	lookup = bson.D{
 		{Key: "$lookup",
 			Value: bson.D{
 				{Key: "from", Value: populate.Collection},
 				{Key: "localField", Value: populate.LocalField},
 				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: populate.As},
+				{Key: "as", Value: lookupAs},
 			},
 		},
 	}
 
-	addFields :=
-
-		bson.D{
-			{Key: "$addFields",
+	field = bson.E{Key: as,
+		Value: bson.D{
+			{Key: "$cond",
 				Value: bson.D{
-					{Key: populate.As,
-						Value: bson.D{
-							{Key: "$cond",
-								Value: bson.D{
-									{Key: "if", Value: bson.D{{Key: "$isArray", Value: "$" + populate.LocalField}}},
-									{Key: "then", Value: "$" + populate.As},
-									{Key: "else", Value: bson.D{{Key: "$first", Value: "$" + populate.As}}},
-								},
-							},
-						},
-					},
+					{Key: "if", Value: bson.D{{Key: "$isArray", Value: "$" + populate.LocalField}}},
+					{Key: "then", Value: "$" + lookupAs},
+					{Key: "else", Value: bson.D{{Key: "$first", Value: "$" + lookupAs}}},
 				},
 			},
-		}
+		},
+	}
 
-	expansion := []bson.D{lookup, addFields}
+	return lookup, field, temporary
+}
 
-	return expansion
+// independentPopulations reports whether no entry of populate reads or overwrites a field populated by another.
+func independentPopulations(populate []PopulateOptions) bool {
+	overlaps := func(a, b string) bool {
+		return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+	}
+
+	for i, p := range populate {
+		as := p.As
+		if as == "" {
+			as = p.LocalField
+		}
+		for j, other := range populate {
+			if i != j && (overlaps(as, other.LocalField) || overlaps(as, other.As)) {
+				return false
+			}
+		}
+	}
+
+	return true
 }
@@ -0,0 +1,60 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// earthRadiusMeters converts distances to the radians $centerSphere expects.
+const earthRadiusMeters = 6378100
+
+var ErrNegativeDistance = errors.New("distance must be greater than zero")
+
+func geoPoint(longitude, latitude float64) bson.M {
+	return bson.M{"type": "Point", "coordinates": bson.A{longitude, latitude}}
+}
+
+// FindNear returns the documents matching filter whose geoField lies between minDistMeters and maxDistMeters
+// of the point, nearest first. geoField needs a 2dsphere index.
+func (mf *Model) FindNear(ctx context.Context, geoField string, longitude, latitude float64, maxDistMeters, minDistMeters float64, filter bson.M, results interface{}) error {
+	if maxDistMeters <= 0 || minDistMeters < 0 {
+		return ErrNegativeDistance
+	}
+
+	nearSphere := bson.M{
+		"$geometry":    geoPoint(longitude, latitude),
+		"$maxDistance": maxDistMeters,
+	}
+	if minDistMeters > 0 {
+		nearSphere["$minDistance"] = minDistMeters
+	}
+
+	near := bson.M{geoField: bson.M{"$nearSphere": nearSphere}}
+	if len(filter) > 0 {
+		near = bson.M{"$and": bson.A{near, filter}}
+	}
+
+	return mf.find(ctx, near, results)
+}
+
+// FindNearPaginated works like PaginatedFind on the documents whose geoField lies within maxDist meters of the point.
+// Pages follow params.PaginatedField, not the distance: cursor pagination needs a stable sort key.
+func (mf *Model) FindNearPaginated(ctx context.Context, params PaginationFindParams, geoField string, lng, lat, maxDist float64, results interface{}) (Page, error) {
+	if maxDist <= 0 {
+		return Page{}, ErrNegativeDistance
+	}
+
+	within := bson.M{geoField: bson.M{"$geoWithin": bson.M{
+		"$centerSphere": bson.A{bson.A{lng, lat}, maxDist / earthRadiusMeters},
+	}}}
+
+	if len(params.Query) > 0 {
+		params.Query = bson.M{"$and": bson.A{within, params.Query}}
+	} else {
+		params.Query = within
+	}
+
+	return mf.PaginatedFindContext(ctx, params, results)
+}
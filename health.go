@@ -0,0 +1,58 @@
+package yamgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type healthResponse struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthHandler answers 200 with the ping latency when the server of the model is reachable, 503 otherwise.
+func HealthHandler(db *Model) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), ShortTimeout*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		err := PingClient(ctx, db.NativeClient())
+		writeHealth(w, time.Since(start), err)
+	})
+}
+
+// ReadinessHandler answers 200 when the model collection can be written and read, see ValidateConnection, 503 otherwise.
+func ReadinessHandler(db *Model) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		report := db.ValidateConnection(r.Context())
+		writeHealth(w, time.Since(start), report.err())
+	})
+}
+
+// LivenessHandler answers 200 when the server of the model is reachable, 503 otherwise, without reporting the latency.
+func LivenessHandler(db *Model) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), ShortTimeout*time.Second)
+		defer cancel()
+
+		writeHealth(w, 0, PingClient(ctx, db.NativeClient()))
+	})
+}
+
+func writeHealth(w http.ResponseWriter, latency time.Duration, err error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(healthResponse{Status: "error", Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(healthResponse{Status: "ok", LatencyMs: float64(latency.Microseconds()) / 1000})
+}
@@ -0,0 +1,44 @@
+package yamgo
+
+// Hook is run against a document before it is written, aborting the operation if it returns an error.
+type Hook func(document interface{}) error
+
+// WithBeforeInsert registers a hook run on every document passed to the insert operations.
+func WithBeforeInsert(hook Hook) Option {
+	return func(m *Model) error {
+		m.beforeInsert = append(m.beforeInsert, hook)
+		return nil
+	}
+}
+
+// WithBeforeUpdate registers a hook run on every replacement or update document.
+func WithBeforeUpdate(hook Hook) Option {
+	return func(m *Model) error {
+		m.beforeUpdate = append(m.beforeUpdate, hook)
+		return nil
+	}
+}
+
+func (mf *Model) runBeforeInsert(document interface{}) error {
+	if err := mf.Validate(document); err != nil {
+		return err
+	}
+
+	for _, hook := range mf.beforeInsert {
+		if err := hook(document); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mf *Model) runBeforeUpdate(document interface{}) error {
+	for _, hook := range mf.beforeUpdate {
+		if err := hook(document); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
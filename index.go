@@ -0,0 +1,144 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidPartialFilter = errors.New("invalid partial filter expression")
+
+var ErrIndexNotFound = errors.New("index not found")
+
+// operators MongoDB rejects in partial filter expressions
+var unsupportedPartialFilterOperators = []string{"$text", "$where", "$geoNear"}
+
+type IndexSpec struct {
+	Keys   bson.D
+	Name   string
+	Unique bool
+	// PartialFilter restricts the index to the documents matching it.
+	PartialFilter bson.M
+}
+
+// CreateIndex creates the index described by spec and returns its name.
+func (mf *Model) CreateIndex(ctx context.Context, spec IndexSpec) (string, error) {
+
+	if len(spec.Keys) == 0 {
+		return "", errors.New("index keys can't be empty")
+	}
+
+	indexOptions := options.Index()
+	if spec.Name != "" {
+		indexOptions.SetName(spec.Name)
+	}
+	if spec.Unique {
+		indexOptions.SetUnique(true)
+	}
+	if spec.PartialFilter != nil {
+		if err := validatePartialFilter(spec.PartialFilter); err != nil {
+			return "", err
+		}
+		indexOptions.SetPartialFilterExpression(spec.PartialFilter)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+
+	return mf.col.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: spec.Keys, Options: indexOptions})
+}
+
+func validatePartialFilter(filter interface{}) error {
+	switch f := filter.(type) {
+	case bson.M:
+		for key, value := range f {
+			for _, op := range unsupportedPartialFilterOperators {
+				if key == op {
+					return fmt.Errorf("%w: %s is not supported", ErrInvalidPartialFilter, op)
+				}
+			}
+			if err := validatePartialFilter(value); err != nil {
+				return err
+			}
+		}
+	case bson.D:
+		for _, elem := range f {
+			if err := validatePartialFilter(bson.M{elem.Key: elem.Value}); err != nil {
+				return err
+			}
+		}
+	case []bson.M:
+		for _, elem := range f {
+			if err := validatePartialFilter(elem); err != nil {
+				return err
+			}
+		}
+	case bson.A:
+		for _, elem := range f {
+			if err := validatePartialFilter(elem); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WaitForIndex polls the collection indexes every pollInterval until indexName exists or ctx expires.
+func (mf *Model) WaitForIndex(ctx context.Context, indexName string, pollInterval time.Duration) error {
+
+	if pollInterval <= 0 {
+		return errors.New("poll interval must be greater than zero")
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		exists, err := mf.indexExists(ctx, indexName)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("index %s not found: %w", indexName, ctx.Err())
+			}
+			return err
+		}
+
+		if exists {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("index %s not found: %w", indexName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (mf *Model) indexExists(ctx context.Context, indexName string) (bool, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, ShortTimeout*time.Second)
+	defer cancel()
+
+	cur, err := mf.col.Indexes().List(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var indexes []bson.M
+	if err = cur.All(ctx, &indexes); err != nil {
+		return false, err
+	}
+
+	for _, index := range indexes {
+		if index["name"] == indexName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
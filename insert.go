@@ -2,35 +2,281 @@ package yamgo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (mf *Model) InsertOne(record interface{}) (res *mongo.InsertOneResult, err error) {
+	return mf.insertOne(context.Background(), record)
+}
+
+// InsertOneContext is the context aware equivalent of InsertOne.
+func (mf *Model) InsertOneContext(ctx context.Context, record interface{}) (*mongo.InsertOneResult, error) {
+	return mf.insertOne(ctx, record)
+}
+
+func (mf *Model) insertOne(ctx context.Context, record interface{}) (res *mongo.InsertOneResult, err error) {
+
+	if err = mf.runBeforeInsert(record); err != nil {
+		return nil, err
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), MediumTimeout*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
 
 	defer cancel()
+	defer mf.logSlowQuery("InsertOne", nil, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = 1
+		}
+		mf.observe("InsertOne", nil, nil, count, start, err)
+	}(time.Now())
+
 	res, err = mf.col.InsertOne(ctx, record)
 
 	if err != nil {
-		return nil, err
+		return nil, mf.WrapError("InsertOne", err)
 	}
 
 	return res, err
 }
 
 func (mf *Model) InsertMany(records []interface{}) (res *mongo.InsertManyResult, err error) {
+	return mf.insertMany(context.Background(), records)
+}
+
+// InsertManyContext is the context aware equivalent of InsertMany.
+func (mf *Model) InsertManyContext(ctx context.Context, records []interface{}) (*mongo.InsertManyResult, error) {
+	return mf.insertMany(ctx, records)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), LongTimeout*time.Second)
+func (mf *Model) insertMany(ctx context.Context, records []interface{}, opts ...*options.InsertManyOptions) (res *mongo.InsertManyResult, err error) {
+
+	for _, record := range records {
+		if err = mf.runBeforeInsert(record); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
 	defer cancel()
+	defer mf.logSlowQuery("InsertMany", nil, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = int64(len(res.InsertedIDs))
+		}
+		mf.observe("InsertMany", nil, nil, count, start, err)
+	}(time.Now())
 
-	res, err = mf.col.InsertMany(ctx, records)
+	res, err = mf.col.InsertMany(ctx, records, opts...)
 
 	if err != nil {
-		return nil, err
+		return nil, mf.WrapError("InsertMany", err)
 	}
 
 	return res, err
 }
+
+// InsertOneIfAbsent inserts document unless another document with the same uniqueFields values exists.
+// On a duplicate key error the ID of the existing document is returned with inserted false.
+// uniqueFields defaults to _id and supports dotted paths.
+func (mf *Model) InsertOneIfAbsent(ctx context.Context, document interface{}, uniqueFields ...string) (id primitive.ObjectID, inserted bool, err error) {
+
+	if err = mf.runBeforeInsert(document); err != nil {
+		return primitive.NilObjectID, false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("InsertOneIfAbsent", nil, time.Now())
+
+	res, err := mf.col.InsertOne(ctx, document)
+	if err == nil {
+		id, ok := res.InsertedID.(primitive.ObjectID)
+		if !ok {
+			return primitive.NilObjectID, true, fmt.Errorf("inserted _id is not an ObjectID: %v", res.InsertedID)
+		}
+		return id, true, nil
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return primitive.NilObjectID, false, err
+	}
+
+	raw, err := bson.MarshalWithRegistry(mf.bsonRegistry(), document)
+	if err != nil {
+		return primitive.NilObjectID, false, err
+	}
+
+	filter, err := uniqueFilter(raw, uniqueFields)
+	if err != nil {
+		return primitive.NilObjectID, false, err
+	}
+
+	var existing struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err = mf.col.FindOne(ctx, mf.interceptQuery(filter), options.FindOne().SetProjection(bson.M{"_id": 1})).Decode(&existing)
+	if err != nil {
+		return primitive.NilObjectID, false, err
+	}
+
+	return existing.ID, false, nil
+}
+
+// uniqueFilter builds a filter matching the values of fields in document.
+func uniqueFilter(document bson.Raw, fields []string) (bson.M, error) {
+	if len(fields) == 0 {
+		fields = []string{"_id"}
+	}
+
+	filter := bson.M{}
+	for _, field := range fields {
+		value, err := document.LookupErr(strings.Split(field, ".")...)
+		if err != nil {
+			return nil, errors.New("unique field " + field + " is missing from the document")
+		}
+		filter[field] = value
+	}
+
+	return filter, nil
+}
+
+// FindOneOrInsert decodes into result the document matching filter, inserting document first if none matches.
+// It runs as a single upsert, so concurrent calls don't race as long as a unique index covers the filter fields.
+func (mf *Model) FindOneOrInsert(ctx context.Context, filter bson.M, document interface{}, result interface{}) (created bool, err error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	if err = mf.runBeforeInsert(document); err != nil {
+		return false, err
+	}
+
+	raw, err := bson.MarshalWithRegistry(mf.bsonRegistry(), document)
+	if err != nil {
+		return false, err
+	}
+
+	query, err := bson.MarshalWithRegistry(mf.bsonRegistry(), filter)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindOneOrInsert", filter, time.Now())
+
+	// findAndModify is run directly as the driver helper doesn't report whether the document was upserted
+	var res struct {
+		LastErrorObject struct {
+			UpdatedExisting bool `bson:"updatedExisting"`
+		} `bson:"lastErrorObject"`
+		Value bson.Raw `bson:"value"`
+	}
+	err = mf.col.Database().RunCommand(ctx, bson.D{
+		{Key: "findAndModify", Value: mf.col.Name()},
+		{Key: "query", Value: bson.Raw(query)},
+		{Key: "update", Value: bson.D{{Key: "$setOnInsert", Value: bson.Raw(raw)}}},
+		{Key: "upsert", Value: true},
+		{Key: "new", Value: true},
+	}).Decode(&res)
+	if err != nil {
+		return false, err
+	}
+
+	if err = mf.decodeRaw(res.Value, result); err != nil {
+		return false, err
+	}
+
+	return !res.LastErrorObject.UpdatedExisting, nil
+}
+
+// InsertIfNotExistsMany inserts the documents whose uniqueField value isn't in the collection yet, e.g. to seed
+// immutable reference data, and returns how many were inserted. The existing values are fetched in a single
+// query. Without a unique index on uniqueField, concurrent calls may insert the same value twice.
+func (mf *Model) InsertIfNotExistsMany(ctx context.Context, documents []interface{}, uniqueField string) (int64, error) {
+
+	if uniqueField == "" {
+		return 0, errors.New("unique field can't be empty")
+	}
+
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	values := make([]bson.RawValue, len(documents))
+	for i, document := range documents {
+		raw, err := bson.MarshalWithRegistry(mf.bsonRegistry(), document)
+		if err != nil {
+			return 0, err
+		}
+
+		values[i], err = bson.Raw(raw).LookupErr(strings.Split(uniqueField, ".")...)
+		if err != nil {
+			return 0, errors.New("unique field " + uniqueField + " is missing from the document")
+		}
+	}
+
+	filter := mf.interceptQuery(bson.M{uniqueField: bson.M{"$in": values}})
+
+	distinctCtx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+
+	existing, err := mf.col.Distinct(distinctCtx, uniqueField, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, value := range existing {
+		t, data, err := bson.MarshalValue(value)
+		if err != nil {
+			return 0, err
+		}
+		seen[uniqueValueKey(bson.RawValue{Type: t, Value: data})] = true
+	}
+
+	missing := []interface{}{}
+	for i, document := range documents {
+		key := uniqueValueKey(values[i])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		missing = append(missing, document)
+	}
+
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	res, err := mf.insertMany(ctx, missing)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(res.InsertedIDs)), nil
+}
+
+// uniqueValueKey identifies value the way MongoDB compares it, numbers being equal whatever their type.
+func uniqueValueKey(value bson.RawValue) string {
+	switch value.Type {
+	case bson.TypeInt32, bson.TypeInt64:
+		return fmt.Sprintf("number:%v", float64(value.AsInt64()))
+	case bson.TypeDouble:
+		return fmt.Sprintf("number:%v", value.Double())
+	default:
+		return value.Type.String() + ":" + string(value.Value)
+	}
+}
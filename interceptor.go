@@ -0,0 +1,131 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithQueryInterceptor sets a function rewriting every filter before it is sent to MongoDB,
+// e.g. to add a tenant condition to all the queries of the model.
+// The interceptor receives a copy of the caller filter and can modify it in place.
+func WithQueryInterceptor(fn func(filter bson.M) bson.M) Option {
+	return func(m *Model) error {
+		if fn == nil {
+			return errors.New("query interceptor can't be nil")
+		}
+		m.queryInterceptor = fn
+		return nil
+	}
+}
+
+func (mf *Model) interceptQuery(filter bson.M) bson.M {
+	if mf.queryInterceptor == nil {
+		return filter
+	}
+
+	f := make(bson.M, len(filter))
+	for key, value := range filter {
+		f[key] = value
+	}
+
+	return mf.queryInterceptor(f)
+}
+
+// WithResultInterceptor sets a function transforming every document returned by the find methods
+// before it is decoded, e.g. to decrypt fields. An error returned by fn fails the whole operation.
+func WithResultInterceptor(fn func(raw bson.Raw) (bson.Raw, error)) Option {
+	return func(m *Model) error {
+		if fn == nil {
+			return errors.New("result interceptor can't be nil")
+		}
+		m.resultInterceptor = fn
+		return nil
+	}
+}
+
+func (mf *Model) interceptResult(raw bson.Raw) (bson.Raw, error) {
+	if mf.resultInterceptor == nil {
+		return raw, nil
+	}
+	return mf.resultInterceptor(raw)
+}
+
+func (mf *Model) decodeRaw(raw bson.Raw, result interface{}) error {
+	raw, err := mf.interceptResult(raw)
+	if err != nil {
+		return err
+	}
+
+	return bson.UnmarshalWithRegistry(mf.bsonRegistry(), raw, result)
+}
+
+func (mf *Model) decodeSingleResult(res *mongo.SingleResult, result interface{}) error {
+	if mf.resultInterceptor == nil {
+		return res.Decode(result)
+	}
+
+	raw, err := res.DecodeBytes()
+	if err != nil {
+		return err
+	}
+
+	return mf.decodeRaw(raw, result)
+}
+
+// decodeAll works like cur.All but runs the result interceptor on every document.
+func (mf *Model) decodeAll(ctx context.Context, cur *mongo.Cursor, results interface{}) error {
+	if mf.resultInterceptor == nil {
+		return cur.All(ctx, results)
+	}
+
+	defer cur.Close(ctx)
+
+	resultsVal := reflect.ValueOf(results)
+	if resultsVal.Kind() != reflect.Ptr || resultsVal.Elem().Kind() != reflect.Slice {
+		return errors.New("results argument must be a pointer to a slice")
+	}
+
+	sliceVal := resultsVal.Elem().Slice(0, 0)
+	elemType := sliceVal.Type().Elem()
+
+	for cur.Next(ctx) {
+		elem := reflect.New(elemType)
+		if err := mf.decodeRaw(cur.Current, elem.Interface()); err != nil {
+			return err
+		}
+		sliceVal = reflect.Append(sliceVal, elem.Elem())
+	}
+
+	if err := cur.Err(); err != nil {
+		return err
+	}
+
+	resultsVal.Elem().Set(sliceVal)
+	return nil
+}
+
+// decodeRaws decodes docs into the slice results points to, running the result interceptor on every document.
+func (mf *Model) decodeRaws(docs []bson.Raw, results interface{}) error {
+	resultsVal := reflect.ValueOf(results)
+	if resultsVal.Kind() != reflect.Ptr || resultsVal.Elem().Kind() != reflect.Slice {
+		return errors.New("results argument must be a pointer to a slice")
+	}
+
+	sliceVal := reflect.MakeSlice(resultsVal.Elem().Type(), 0, len(docs))
+	elemType := sliceVal.Type().Elem()
+
+	for _, doc := range docs {
+		elem := reflect.New(elemType)
+		if err := mf.decodeRaw(doc, elem.Interface()); err != nil {
+			return err
+		}
+		sliceVal = reflect.Append(sliceVal, elem.Elem())
+	}
+
+	resultsVal.Elem().Set(sliceVal)
+	return nil
+}
@@ -0,0 +1,113 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LocksCollection stores the locks taken with LockCollection.
+const LocksCollection = "_locks"
+
+var ErrLockHeld = errors.New("lock is held by another holder")
+
+// minLockTTL leaves the refresh of a lock, every ttl/3, the time of a round trip to the server.
+const minLockTTL = 100 * time.Millisecond
+
+// Lock is a lock taken with LockCollection, its expiry is pushed back every ttl/3 until Unlock is called.
+type Lock struct {
+	name  string
+	token primitive.ObjectID
+	col   *mongo.Collection
+	stop  chan struct{}
+	once  *sync.Once
+}
+
+// LockCollection takes the lock lockName for ttl, returning ErrLockHeld if another holder has it and it has not expired.
+// The lock document records the hostname of the holder. If the process dies the lock is released once ttl has elapsed.
+// ttl must be at least 100ms.
+func LockCollection(ctx context.Context, lockName string, ttl time.Duration) (Lock, error) {
+	if ttl < minLockTTL {
+		return Lock{}, fmt.Errorf("lock ttl must be at least %s", minLockTTL)
+	}
+
+	holder, _ := os.Hostname()
+
+	lock := Lock{
+		name:  lockName,
+		token: primitive.NewObjectID(),
+		col:   GetCollection(LocksCollection),
+		stop:  make(chan struct{}),
+		once:  &sync.Once{},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ShortTimeout*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	// an expired lock matches and is taken over, a live one does not and the upsert hits the unique _id
+	_, err := lock.col.UpdateOne(ctx,
+		bson.M{"_id": lockName, "expiry": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"holder": holder, "token": lock.token, "expiry": now.Add(ttl)}},
+		options.Update().SetUpsert(true),
+	)
+
+	if mongo.IsDuplicateKeyError(err) {
+		return Lock{}, ErrLockHeld
+	}
+	if err != nil {
+		return Lock{}, err
+	}
+
+	go lock.refresh(ttl)
+
+	return lock, nil
+}
+
+func (l Lock) refresh(ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), ShortTimeout*time.Second)
+			res, err := l.col.UpdateOne(ctx,
+				bson.M{"_id": l.name, "token": l.token},
+				bson.M{"$set": bson.M{"expiry": time.Now().Add(ttl)}},
+			)
+			cancel()
+
+			// the lock expired and was taken over, there is nothing left to refresh
+			if err == nil && res.MatchedCount == 0 {
+				return
+			}
+		}
+	}
+}
+
+// Unlock releases the lock, unless it expired and was taken by another holder.
+func (l Lock) Unlock(ctx context.Context) error {
+	if l.once == nil {
+		return errors.New("lock was not taken")
+	}
+
+	l.once.Do(func() { close(l.stop) })
+
+	ctx, cancel := context.WithTimeout(ctx, ShortTimeout*time.Second)
+	defer cancel()
+
+	_, err := l.col.DeleteOne(ctx, bson.M{"_id": l.name, "token": l.token})
+	return err
+}
@@ -0,0 +1,85 @@
+package yamgo
+
+import (
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const redactedValue = "***"
+
+// RedactFields returns a filter sanitizer replacing the values of the given fields with "***".
+// Nested documents are redacted as well.
+func RedactFields(fields ...string) func(bson.M) bson.M {
+	redacted := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		redacted[field] = true
+	}
+
+	var redact func(filter bson.M) bson.M
+	redact = func(filter bson.M) bson.M {
+		if filter == nil {
+			return nil
+		}
+
+		res := make(bson.M, len(filter))
+		for key, value := range filter {
+			switch v := value.(type) {
+			case bson.M:
+				res[key] = redact(v)
+			case []bson.M:
+				docs := make([]bson.M, len(v))
+				for i, doc := range v {
+					docs[i] = redact(doc)
+				}
+				res[key] = docs
+			case bson.A:
+				values := make(bson.A, len(v))
+				for i, elem := range v {
+					if doc, ok := elem.(bson.M); ok {
+						values[i] = redact(doc)
+					} else {
+						values[i] = elem
+					}
+				}
+				res[key] = values
+			default:
+				res[key] = value
+			}
+
+			if redacted[key] {
+				res[key] = redactedValue
+			}
+		}
+		return res
+	}
+
+	return redact
+}
+
+func (mf *Model) sanitizeFilter(filter bson.M) bson.M {
+	if mf.filterSanitizer == nil {
+		return filter
+	}
+	return mf.filterSanitizer(filter)
+}
+
+func (mf *Model) logSlowQuery(op string, filter bson.M, start time.Time) {
+	if mf.slowQueryLogger == nil {
+		return
+	}
+
+	duration := time.Since(start)
+	if duration <= mf.slowQueryThreshold {
+		return
+	}
+
+	mf.slowQueryLogger.Warn("yamgo: slow query",
+		slog.String("op", op),
+		slog.String("collection", mf.col.Name()),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+		slog.Any("filter", mf.sanitizeFilter(filter)),
+		slog.Int64("threshold_ms", mf.slowQueryThreshold.Milliseconds()),
+	)
+}
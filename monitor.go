@@ -0,0 +1,100 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WithConnectionPoolMonitor registers monitor to receive the connection pool events of the client.
+func WithConnectionPoolMonitor(monitor *event.PoolMonitor) ClientOption {
+	return func(o *options.ClientOptions) error {
+		if monitor == nil {
+			return errors.New("pool monitor can't be nil")
+		}
+		o.SetPoolMonitor(monitor)
+		return nil
+	}
+}
+
+// DefaultPoolLogger returns a pool monitor logging connection pool events to logger.
+// Failed checkouts are logged at WARN, created and closed connections at INFO and everything else at DEBUG.
+func DefaultPoolLogger(logger *slog.Logger) *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			level := slog.LevelDebug
+			switch e.Type {
+			case event.GetFailed:
+				level = slog.LevelWarn
+			case event.ConnectionCreated, event.ConnectionClosed:
+				level = slog.LevelInfo
+			}
+
+			attrs := []slog.Attr{
+				slog.String("type", e.Type),
+				slog.String("address", e.Address),
+				slog.Uint64("connection_id", e.ConnectionID),
+			}
+
+			if e.Reason != "" {
+				attrs = append(attrs, slog.String("reason", e.Reason))
+			}
+
+			logger.LogAttrs(context.Background(), level, "yamgo: connection pool event", attrs...)
+		},
+	}
+}
+
+// WithCommandMonitor registers monitor to receive the started, succeeded and failed events of every command.
+func WithCommandMonitor(monitor *event.CommandMonitor) ClientOption {
+	return func(o *options.ClientOptions) error {
+		if monitor == nil {
+			return errors.New("command monitor can't be nil")
+		}
+		o.SetMonitor(monitor)
+		return nil
+	}
+}
+
+// NewCommandLogger returns a command monitor logging every command to logger.
+// The values of redactFields are masked in the logged commands.
+// Started and succeeded commands are logged at DEBUG, failed ones at WARN.
+func NewCommandLogger(logger *slog.Logger, redactFields []string) *event.CommandMonitor {
+	redact := RedactFields(redactFields...)
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			var command bson.M
+			if err := bson.Unmarshal(e.Command, &command); err != nil {
+				command = bson.M{}
+			}
+
+			logger.LogAttrs(ctx, slog.LevelDebug, "yamgo: command started",
+				slog.String("command_name", e.CommandName),
+				slog.Int64("request_id", e.RequestID),
+				slog.String("database", e.DatabaseName),
+				slog.Any("command", redact(command)),
+			)
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "yamgo: command succeeded",
+				slog.String("command_name", e.CommandName),
+				slog.Int64("request_id", e.RequestID),
+				slog.Float64("duration_ms", float64(e.DurationNanos)/float64(time.Millisecond)),
+			)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			logger.LogAttrs(ctx, slog.LevelWarn, "yamgo: command failed",
+				slog.String("command_name", e.CommandName),
+				slog.Int64("request_id", e.RequestID),
+				slog.Float64("duration_ms", float64(e.DurationNanos)/float64(time.Millisecond)),
+				slog.String("failure", e.Failure),
+			)
+		},
+	}
+}
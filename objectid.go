@@ -0,0 +1,185 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidID = errors.New("invalid object id")
+
+// ConvertToObjectID parses a hex string into an ObjectID, returning ErrInvalidID if it is malformed.
+func ConvertToObjectID(id string) (primitive.ObjectID, error) {
+	oID, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+
+	return oID, nil
+}
+
+// MustConvertToObjectID is like ConvertToObjectID but panics if id is invalid.
+func MustConvertToObjectID(id string) primitive.ObjectID {
+	oID, err := ConvertToObjectID(id)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return oID
+}
+
+func IsValidObjectID(id string) bool {
+	return primitive.IsValidObjectID(id)
+}
+
+func NewObjectID() primitive.ObjectID {
+	return primitive.NewObjectID()
+}
+
+// ObjectIDFromTime returns the smallest ObjectID generated at t, useful as a range boundary.
+func ObjectIDFromTime(t time.Time) primitive.ObjectID {
+	return primitive.NewObjectIDFromTimestamp(t)
+}
+
+// ObjectIDToTime returns the creation time embedded in id, truncated to the second.
+func ObjectIDToTime(id primitive.ObjectID) time.Time {
+	return id.Timestamp()
+}
+
+// FindCreatedBetween finds the documents whose _id was generated in [from, to).
+func (mf *Model) FindCreatedBetween(ctx context.Context, from, to time.Time, results interface{}) error {
+
+	filter := mf.interceptQuery(bson.M{"_id": bson.M{"$gte": ObjectIDFromTime(from), "$lt": ObjectIDFromTime(to)}})
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindCreatedBetween", filter, time.Now())
+
+	cur, err := mf.col.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return mf.decodeAll(ctx, cur, results)
+}
+
+// FindChangedSince finds the documents whose _id was generated at or after since.
+// The ObjectID only records the insertion time, documents updated after since are not returned.
+func (mf *Model) FindChangedSince(ctx context.Context, since time.Time, results interface{}) error {
+
+	cur, err := mf.FindChangedSinceCursor(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+
+	return mf.decodeAll(ctx, cur, results)
+}
+
+// FindChangedSinceCursor is the streaming version of FindChangedSince, the caller must close the cursor.
+func (mf *Model) FindChangedSinceCursor(ctx context.Context, since time.Time) (*Cursor, error) {
+
+	filter := mf.interceptQuery(bson.M{"_id": bson.M{"$gte": ObjectIDFromTime(since)}})
+
+	defer mf.logSlowQuery("FindChangedSince", filter, time.Now())
+
+	return mf.col.Find(ctx, filter)
+}
+
+// FindObjectIDs returns the _id of the documents matching filter, only _id is fetched.
+func (mf *Model) FindObjectIDs(ctx context.Context, filter bson.M) ([]primitive.ObjectID, error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindObjectIDs", filter, time.Now())
+
+	cur, err := mf.col.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	ids := []primitive.ObjectID{}
+	for cur.Next(ctx) {
+		raw, err := mf.interceptResult(cur.Current)
+		if err != nil {
+			return nil, err
+		}
+
+		id, ok := raw.Lookup("_id").ObjectIDOK()
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidID, raw.Lookup("_id"))
+		}
+		ids = append(ids, id)
+	}
+
+	if err = cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// FindStringIDs is like FindObjectIDs but returns the IDs as hex strings.
+func (mf *Model) FindStringIDs(ctx context.Context, filter bson.M) ([]string, error) {
+	ids, err := mf.FindObjectIDs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	hexIDs := make([]string, len(ids))
+	for i, id := range ids {
+		hexIDs[i] = id.Hex()
+	}
+
+	return hexIDs, nil
+}
+
+// HasChanges reports whether a document was inserted after sinceID, e.g. a checkpoint returned by LatestID.
+// Counting stops at the first matching document.
+func (mf *Model) HasChanges(ctx context.Context, sinceID primitive.ObjectID) (bool, error) {
+
+	filter := mf.interceptQuery(bson.M{"_id": bson.M{"$gt": sinceID}})
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("HasChanges", filter, time.Now())
+
+	count, err := mf.col.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// LatestID returns the largest _id of the collection, NilObjectID if it is empty.
+func (mf *Model) LatestID(ctx context.Context) (primitive.ObjectID, error) {
+	var latest struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+
+	err := mf.FindLast(ctx, bson.M{}, "_id", &latest, FindProjection(bson.M{"_id": 1}))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return primitive.NilObjectID, nil
+	}
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	return latest.ID, nil
+}
@@ -0,0 +1,58 @@
+package yamgo
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// OperationEvent describes an operation run by a model, see WithOperationObserver.
+type OperationEvent struct {
+	Operation  string
+	Collection string
+	Filter     bson.M
+	Update     interface{}
+	// Count is the number of documents returned, inserted, matched or deleted.
+	Count    int64
+	Duration time.Duration
+	Err      error
+}
+
+// WithOperationObserver sets a function called after every core operation of the model
+// (finds, counts, inserts, updates and deletes) completes, successfully or not.
+func WithOperationObserver(fn func(OperationEvent)) Option {
+	return func(m *Model) error {
+		if fn == nil {
+			return errors.New("operation observer can't be nil")
+		}
+		m.operationObserver = fn
+		return nil
+	}
+}
+
+func (mf *Model) observe(op string, filter bson.M, update interface{}, count int64, start time.Time, err error) {
+	if mf.operationObserver == nil {
+		return
+	}
+
+	mf.operationObserver(OperationEvent{
+		Operation:  op,
+		Collection: mf.col.Name(),
+		Filter:     filter,
+		Update:     update,
+		Count:      count,
+		Duration:   time.Since(start),
+		Err:        err,
+	})
+}
+
+// resultsLen returns the length of the slice results points to, 0 if it isn't one.
+func resultsLen(results interface{}) int64 {
+	v := reflect.ValueOf(results)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return 0
+	}
+	return int64(v.Elem().Len())
+}
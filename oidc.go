@@ -0,0 +1,120 @@
+package yamgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// oidcRefreshMargin is how long before its expiry an access token is renewed.
+const oidcRefreshMargin = time.Minute
+
+// WithOIDCAuth authenticates the client with MONGODB-OIDC, requesting access tokens from tokenEndpoint
+// with the OAuth 2.0 client credentials grant. Tokens are cached and renewed shortly before they expire.
+func WithOIDCAuth(tokenEndpoint, clientID, clientSecret string, scopes []string) ClientOption {
+	return func(o *options.ClientOptions) error {
+		if tokenEndpoint == "" || clientID == "" {
+			return errors.New("oidc token endpoint and client id can't be empty")
+		}
+
+		source := &oidcTokenSource{
+			tokenEndpoint: tokenEndpoint,
+			clientID:      clientID,
+			clientSecret:  clientSecret,
+			scopes:        scopes,
+		}
+
+		o.SetAuth(options.Credential{
+			AuthMechanism:       "MONGODB-OIDC",
+			OIDCMachineCallback: source.callback,
+		})
+		return nil
+	}
+}
+
+type oidcTokenSource struct {
+	tokenEndpoint string
+	clientID      string
+	clientSecret  string
+	scopes        []string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (s *oidcTokenSource) callback(ctx context.Context, _ *options.OIDCArgs) (*options.OIDCCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// tokens without expiry are not cached
+	if s.token == "" || !time.Now().Before(s.expiresAt) {
+		if err := s.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	credential := &options.OIDCCredential{AccessToken: s.token}
+	if !s.expiresAt.IsZero() {
+		expiresAt := s.expiresAt
+		credential.ExpiresAt = &expiresAt
+	}
+	return credential, nil
+}
+
+func (s *oidcTokenSource) refresh(ctx context.Context) error {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.clientID},
+		"client_secret": {s.clientSecret},
+	}
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not fetch oidc token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not fetch oidc token: unexpected status %s", resp.Status)
+	}
+
+	var token oidcTokenResponse
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("could not decode oidc token: %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return errors.New("oidc token response has no access_token")
+	}
+
+	s.token = token.AccessToken
+	s.expiresAt = time.Time{}
+	if token.ExpiresIn > 0 {
+		s.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - oidcRefreshMargin)
+	}
+	return nil
+}
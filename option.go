@@ -0,0 +1,119 @@
+package yamgo
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/tag"
+)
+
+// Option configures a Model created with NewModel.
+type Option func(*Model) error
+
+// ClientOption configures the client created by Connect.
+type ClientOption func(*options.ClientOptions) error
+
+// FindOption configures a single read operation.
+type FindOption func(*findOptions) error
+
+type findOptions struct {
+	readPrefTags tag.Set
+	maxStaleness time.Duration
+	projection   bson.M
+	comment      string
+	textScore    bool
+
+	includeDeleted bool
+}
+
+func applyFindOptions(opts []FindOption) (findOptions, error) {
+	var o findOptions
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
+
+// FindProjection limits the fields decoded into the result.
+func FindProjection(projection bson.M) FindOption {
+	return func(o *findOptions) error {
+		o.projection = projection
+		return nil
+	}
+}
+
+// Comment attaches comment to the query, it shows in the profiler and the server logs.
+func Comment(comment string) FindOption {
+	return func(o *findOptions) error {
+		o.comment = comment
+		return nil
+	}
+}
+
+// WithComment attaches comment to the query, like Comment.
+func WithComment(comment string) FindOption {
+	return Comment(comment)
+}
+
+// TextScoreField is the field TextScoreSort stores the text search score in,
+// prefixed so that it doesn't collide with a score field of the documents.
+const TextScoreField = "_textScore"
+
+// TextScoreSort sorts the results of a $text query by relevance, best match first.
+// The score is projected in TextScoreField so it can be decoded too.
+func TextScoreSort() FindOption {
+	return func(o *findOptions) error {
+		o.textScore = true
+		return nil
+	}
+}
+
+func (o findOptions) projectionWithScore() bson.M {
+	if !o.textScore {
+		return o.projection
+	}
+
+	projection := make(bson.M, len(o.projection)+1)
+	for key, value := range o.projection {
+		projection[key] = value
+	}
+	projection[TextScoreField] = textScoreMeta
+
+	return projection
+}
+
+var textScoreMeta = bson.M{"$meta": "textScore"}
+
+// WithSlowQueryLog logs at WARN level every operation taking longer than threshold.
+func WithSlowQueryLog(threshold time.Duration, logger *slog.Logger) Option {
+	return func(m *Model) error {
+		if logger == nil {
+			return errors.New("slow query logger can't be nil")
+		}
+		m.slowQueryThreshold = threshold
+		m.slowQueryLogger = logger
+		return nil
+	}
+}
+
+// WithFilterSanitizer sets the function used to redact filters before they are logged.
+func WithFilterSanitizer(fn func(bson.M) bson.M) Option {
+	return func(m *Model) error {
+		m.filterSanitizer = fn
+		return nil
+	}
+}
+
+// WithMergeAddFields sets whether the population stages of FindAndPopulate and the paginated finds coalesce
+// their $addFields into a single stage, see BuildLookupStages. They do by default.
+func WithMergeAddFields(merge bool) Option {
+	return func(m *Model) error {
+		m.separateAddFields = !merge
+		return nil
+	}
+}
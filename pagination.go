@@ -32,6 +32,18 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// SeekMethod selects how PaginatedFind seeks to the page after a cursor.
+type SeekMethod int
+
+const (
+	// CursorRange pages on PaginatedField, then _id, with a range query on both.
+	// Sorted pagination needs it, along with an index on PaginatedField and _id to stay fast.
+	CursorRange SeekMethod = iota
+	// KeysetByID pages on _id only and ignores PaginatedField. The default _id index is enough,
+	// so it stays fast on collections without a custom sort index, but pages follow insertion order.
+	KeysetByID
+)
+
 type (
 	PaginationFindParams struct {
 		Query          primitive.M        `form:"query"`
@@ -44,7 +56,18 @@ type (
 		CountTotal     bool               `form:"count_total"`
 		Hint           interface{}        `form:"hint"`
 		Projection     string             `form:"projection"`
-		Expansion      []PopulateOptions
+		Comment        string             `form:"comment"`
+		SeekMethod     SeekMethod         `form:"seek_method"`
+		IncludeDeleted bool               `form:"include_deleted"`
+		// UseStablePages stores the _id of every document of the page in its cursors and excludes them from
+		// the following page, so documents moved in the sort order between two requests are not repeated.
+		// Cursors grow with the page size and the extra $nin condition slows down large pages.
+		UseStablePages bool `form:"use_stable_pages"`
+		// TextScoreSort pages the results of a $text Query by relevance, best match first by default.
+		// The score is added to the documents in TextScoreField and replaces PaginatedField as the cursor field,
+		// so the results must decode it.
+		TextScoreSort bool `form:"text_score_sort"`
+		Expansion     []PopulateOptions
 	}
 
 	Page struct {
@@ -93,21 +116,36 @@ func GenerateCursorQuery(shouldSecondarySortOnID bool, paginatedField string, co
 }
 
 func BuildQueries(p PaginationFindParams) (queries []bson.M, sort bson.D, err error) {
+	queries, cursorQuery, sort, err := buildQueries(p)
+	if err != nil {
+		return queries, nil, err
+	}
+
+	if cursorQuery != nil {
+		queries = append(queries, cursorQuery)
+	}
+
+	return queries, sort, nil
+}
+
+// buildQueries works like BuildQueries but returns the query on the cursor apart from the other ones,
+// since with TextScoreSort it can only match once the score is added to the documents.
+func buildQueries(p PaginationFindParams) (queries []bson.M, cursorQuery bson.M, sort bson.D, err error) {
 	p = ensureMandatoryParams(p)
 	shouldSecondarySortOnID := p.PaginatedField != "_id"
 
 	if p.Limit <= 0 {
-		return []bson.M{}, nil, errors.New("a limit of at least 1 is required")
+		return []bson.M{}, nil, nil, errors.New("a limit of at least 1 is required")
 	}
 
 	nextCursorValues, err := parseCursor(p.Next, shouldSecondarySortOnID)
 	if err != nil {
-		return []bson.M{}, nil, &CursorError{fmt.Errorf("next cursor parse failed: %s", err)}
+		return []bson.M{}, nil, nil, &CursorError{fmt.Errorf("next cursor parse failed: %s", err)}
 	}
 
 	previousCursorValues, err := parseCursor(p.Previous, shouldSecondarySortOnID)
 	if err != nil {
-		return []bson.M{}, nil, &CursorError{fmt.Errorf("previous cursor parse failed: %s", err)}
+		return []bson.M{}, nil, nil, &CursorError{fmt.Errorf("previous cursor parse failed: %s", err)}
 	}
 
 	// Figure out the sort direction and comparison operator that will be used in the augmented query
@@ -121,6 +159,20 @@ func BuildQueries(p PaginationFindParams) (queries []bson.M, sort bson.D, err er
 
 	queries = []bson.M{p.Query}
 
+	if p.UseStablePages {
+		cursor := p.Next
+		if cursor == "" {
+			cursor = p.Previous
+		}
+		seenIDs, err := parseSeenIDs(cursor)
+		if err != nil {
+			return []bson.M{}, nil, nil, &CursorError{fmt.Errorf("seen ids parse failed: %s", err)}
+		}
+		if len(seenIDs) > 0 {
+			queries = append(queries, bson.M{"_id": bson.M{"$nin": seenIDs}})
+		}
+	}
+
 	// Setup the pagination query
 	if p.Next != "" || p.Previous != "" {
 		var cursorValues []interface{}
@@ -129,12 +181,10 @@ func BuildQueries(p PaginationFindParams) (queries []bson.M, sort bson.D, err er
 		} else if p.Previous != "" {
 			cursorValues = previousCursorValues
 		}
-		var cursorQuery bson.M
 		cursorQuery, err = GenerateCursorQuery(shouldSecondarySortOnID, p.PaginatedField, comparisonOp, cursorValues)
 		if err != nil {
-			return []bson.M{}, nil, err
+			return []bson.M{}, nil, nil, err
 		}
-		queries = append(queries, cursorQuery)
 	}
 
 	// Setup the sort query
@@ -144,11 +194,16 @@ func BuildQueries(p PaginationFindParams) (queries []bson.M, sort bson.D, err er
 		sort = bson.D{{Key: "_id", Value: sortDir}}
 	}
 
-	return queries, sort, nil
+	return queries, cursorQuery, sort, nil
 }
 
 func ensureMandatoryParams(p PaginationFindParams) PaginationFindParams {
-	if p.PaginatedField == "" {
+	if p.TextScoreSort {
+		p.PaginatedField = TextScoreField
+		return p
+	}
+
+	if p.PaginatedField == "" || p.SeekMethod == KeysetByID {
 		p.PaginatedField = "_id"
 		p.Collation = nil
 	}
@@ -156,6 +211,9 @@ func ensureMandatoryParams(p PaginationFindParams) PaginationFindParams {
 	return p
 }
 
+// seenIDsKey is the cursor element holding the _id values of the page in stable pages mode.
+const seenIDsKey = "seen"
+
 var parseCursor = func(cursor string, shouldSecondarySortOnID bool) ([]interface{}, error) {
 
 	cursorValues := make([]interface{}, 0, 2)
@@ -164,6 +222,9 @@ var parseCursor = func(cursor string, shouldSecondarySortOnID bool) ([]interface
 		if err != nil {
 			return nil, err
 		}
+		if n := len(parsedCursor); n > 0 && parsedCursor[n-1].Key == seenIDsKey {
+			parsedCursor = parsedCursor[:n-1]
+		}
 		var id interface{}
 		if shouldSecondarySortOnID {
 			if len(parsedCursor) != 2 {
@@ -183,6 +244,27 @@ var parseCursor = func(cursor string, shouldSecondarySortOnID bool) ([]interface
 	return cursorValues, nil
 }
 
+func parseSeenIDs(cursor string) (bson.A, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	parsedCursor, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if n := len(parsedCursor); n > 0 && parsedCursor[n-1].Key == seenIDsKey {
+		seenIDs, ok := parsedCursor[n-1].Value.(bson.A)
+		if !ok {
+			return nil, errors.New("expecting an array of seen ids")
+		}
+		return seenIDs, nil
+	}
+
+	return nil, nil
+}
+
 func decodeCursor(cursor string) (bson.D, error) {
 
 	var cursorData bson.D
@@ -195,7 +277,7 @@ func decodeCursor(cursor string) (bson.D, error) {
 	return cursorData, err
 }
 
-func generateCursor(result interface{}, paginatedField string, shouldSecondarySortOnID bool) (string, error) {
+func generateCursor(result interface{}, paginatedField string, shouldSecondarySortOnID bool, seenIDs bson.A) (string, error) {
 
 	if result == nil {
 		return "", fmt.Errorf("the specified result must be a non nil value")
@@ -233,6 +315,9 @@ func generateCursor(result interface{}, paginatedField string, shouldSecondarySo
 		id := recordAsMap["_id"]
 		cursorData = append(cursorData, bson.E{Key: "_id", Value: id})
 	}
+	if seenIDs != nil {
+		cursorData = append(cursorData, bson.E{Key: seenIDsKey, Value: seenIDs})
+	}
 	// Encode the cursor data into a url safe string
 	cursor, err := encodeCursor(cursorData)
 	if err != nil {
@@ -245,3 +330,83 @@ func encodeCursor(cursorData bson.D) (string, error) {
 	data, err := bson.Marshal(cursorData)
 	return base64.RawURLEncoding.EncodeToString(data), err
 }
+
+// PaginationParamsBuilder builds PaginationFindParams, see NewPaginationParams.
+type PaginationParamsBuilder struct {
+	params PaginationFindParams
+}
+
+// NewPaginationParams returns a builder of PaginationFindParams, e.g.
+// NewPaginationParams().Query(filter).Limit(20).Next(cursor).Build().
+func NewPaginationParams() *PaginationParamsBuilder {
+	return &PaginationParamsBuilder{}
+}
+
+func (b *PaginationParamsBuilder) Query(q bson.M) *PaginationParamsBuilder {
+	b.params.Query = q
+	return b
+}
+
+func (b *PaginationParamsBuilder) Limit(n int64) *PaginationParamsBuilder {
+	b.params.Limit = n
+	return b
+}
+
+func (b *PaginationParamsBuilder) Next(cursor string) *PaginationParamsBuilder {
+	b.params.Next = cursor
+	return b
+}
+
+func (b *PaginationParamsBuilder) Previous(cursor string) *PaginationParamsBuilder {
+	b.params.Previous = cursor
+	return b
+}
+
+func (b *PaginationParamsBuilder) PaginatedField(f string) *PaginationParamsBuilder {
+	b.params.PaginatedField = f
+	return b
+}
+
+// Descending sorts the pages from the largest PaginatedField value, it sets SortAscending to !d.
+func (b *PaginationParamsBuilder) Descending(d bool) *PaginationParamsBuilder {
+	b.params.SortAscending = !d
+	return b
+}
+
+func (b *PaginationParamsBuilder) Collation(c *options.Collation) *PaginationParamsBuilder {
+	b.params.Collation = c
+	return b
+}
+
+func (b *PaginationParamsBuilder) Hint(h interface{}) *PaginationParamsBuilder {
+	b.params.Hint = h
+	return b
+}
+
+func (b *PaginationParamsBuilder) Projection(p string) *PaginationParamsBuilder {
+	b.params.Projection = p
+	return b
+}
+
+func (b *PaginationParamsBuilder) CountTotal(c bool) *PaginationParamsBuilder {
+	b.params.CountTotal = c
+	return b
+}
+
+// Build validates and returns the params: the limit must be at least 1, only one of the next and
+// previous cursors can be set and the projection must be valid.
+func (b *PaginationParamsBuilder) Build() (PaginationFindParams, error) {
+	if b.params.Limit <= 0 {
+		return PaginationFindParams{}, errors.New("a limit of at least 1 is required")
+	}
+
+	if b.params.Next != "" && b.params.Previous != "" {
+		return PaginationFindParams{}, errors.New("next and previous cursors can't both be set")
+	}
+
+	if _, err := ParseProjection(b.params.Projection); err != nil {
+		return PaginationFindParams{}, err
+	}
+
+	return b.params, nil
+}
@@ -0,0 +1,64 @@
+package yamgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInvalidProjection = errors.New("invalid projection")
+
+// ParseProjection parses a comma separated list of fields, e.g. "name,email", into a projection document.
+// Fields prefixed with "-" are excluded instead, e.g. "-password". A bare "id" stands for "_id".
+// Inclusions and exclusions can't be mixed, except for excluding _id along with included fields.
+// An empty projection returns nil.
+func ParseProjection(projection string) (bson.M, error) {
+	if strings.TrimSpace(projection) == "" {
+		return nil, nil
+	}
+
+	included := bson.M{}
+	excluded := bson.M{}
+	for _, field := range strings.Split(projection, ",") {
+		field = strings.TrimSpace(field)
+
+		exclude := strings.HasPrefix(field, "-")
+		if exclude {
+			field = strings.TrimSpace(field[1:])
+		}
+
+		if field == "" {
+			return nil, fmt.Errorf("%w: empty field in %q", ErrInvalidProjection, projection)
+		}
+		if strings.HasPrefix(field, "$") || strings.HasPrefix(field, "-") {
+			return nil, fmt.Errorf("%w: invalid field %q", ErrInvalidProjection, field)
+		}
+		if field == "id" {
+			field = "_id"
+		}
+
+		if exclude {
+			excluded[field] = 0
+		} else {
+			included[field] = 1
+		}
+	}
+
+	if len(included) == 0 {
+		return excluded, nil
+	}
+
+	for field := range excluded {
+		if field != "_id" {
+			return nil, fmt.Errorf("%w: can't mix included and excluded fields in %q", ErrInvalidProjection, projection)
+		}
+		if _, ok := included[field]; ok {
+			return nil, fmt.Errorf("%w: _id is both included and excluded in %q", ErrInvalidProjection, projection)
+		}
+		included[field] = 0
+	}
+
+	return included, nil
+}
@@ -0,0 +1,83 @@
+package yamgo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type YamgoRateLimiter struct {
+	model Model
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	ResetAt   time.Time
+}
+
+type rateLimitCounter struct {
+	Count    int       `bson:"count"`
+	ExpireAt time.Time `bson:"expireAt"`
+}
+
+// NewRateLimiter returns a rate limiter storing its counters in the given collection.
+// A TTL index on the counters expiry is created so that expired windows are removed by MongoDB.
+func NewRateLimiter(collectionName string) (YamgoRateLimiter, error) {
+	limiter := YamgoRateLimiter{model: NewModel(collectionName)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), MediumTimeout*time.Second)
+	defer cancel()
+
+	_, err := limiter.model.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expireAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	if err != nil {
+		return YamgoRateLimiter{}, err
+	}
+
+	return limiter, nil
+}
+
+// RateLimiter registers a hit for key and reports whether it fits in the limit of the current window.
+// The window starts with the first hit and the counter resets once it has elapsed.
+func (rl *YamgoRateLimiter) RateLimiter(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, ShortTimeout*time.Second)
+	defer cancel()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	windowOpen := bson.D{{Key: "$gt", Value: bson.A{"$expireAt", now}}}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "count", Value: bson.D{{Key: "$cond", Value: bson.A{windowOpen, bson.D{{Key: "$add", Value: bson.A{"$count", 1}}}, 1}}}},
+			{Key: "expireAt", Value: bson.D{{Key: "$cond", Value: bson.A{windowOpen, "$expireAt", now.Add(window)}}}},
+		}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counter rateLimitCounter
+	err := rl.model.col.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&counter)
+
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	remaining := limit - counter.Count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return RateLimitResult{
+		Allowed:   counter.Count <= limit,
+		Remaining: remaining,
+		ResetAt:   counter.ExpireAt,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package yamgo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
+)
+
+var (
+	ErrInvalidTagSet       = errors.New("invalid tag set")
+	ErrInvalidMaxStaleness = errors.New("invalid max staleness")
+)
+
+// minMaxStaleness is the smallest maxStalenessSeconds MongoDB accepts.
+const minMaxStaleness = 90 * time.Second
+
+// WithReadPreferenceTags routes the reads of the model to the nearest member matching tags.
+func WithReadPreferenceTags(tags bson.D) Option {
+	return func(m *Model) error {
+		set, err := toTagSet(tags)
+		if err != nil {
+			return err
+		}
+		m.readPrefTags = set
+		return nil
+	}
+}
+
+// ReadPreferenceTags overrides the tag set of the model read preference for a single operation.
+func ReadPreferenceTags(tags bson.D) FindOption {
+	return func(o *findOptions) error {
+		set, err := toTagSet(tags)
+		if err != nil {
+			return err
+		}
+		o.readPrefTags = set
+		return nil
+	}
+}
+
+// WithMaxStaleness excludes from the reads of the model the secondaries lagging more than d behind the primary.
+// Without read preference tags the reads go to secondaries, or to the primary when none is available.
+func WithMaxStaleness(d time.Duration) Option {
+	return func(m *Model) error {
+		if err := validateMaxStaleness(d); err != nil {
+			return err
+		}
+		m.maxStaleness = d
+		return nil
+	}
+}
+
+// MaxStaleness overrides the max staleness of the model read preference for a single operation.
+func MaxStaleness(d time.Duration) FindOption {
+	return func(o *findOptions) error {
+		if err := validateMaxStaleness(d); err != nil {
+			return err
+		}
+		o.maxStaleness = d
+		return nil
+	}
+}
+
+func validateMaxStaleness(d time.Duration) error {
+	if d < minMaxStaleness {
+		return fmt.Errorf("%w: %s is less than the %s minimum", ErrInvalidMaxStaleness, d, minMaxStaleness)
+	}
+	return nil
+}
+
+// ReadPreference returns the read preference configured on the model, nil if the client default is used.
+func (mf *Model) ReadPreference() *readpref.ReadPref {
+	return buildReadPreference(mf.readPrefTags, mf.maxStaleness)
+}
+
+func buildReadPreference(tags tag.Set, maxStaleness time.Duration) *readpref.ReadPref {
+	var opts []readpref.Option
+	if maxStaleness > 0 {
+		opts = append(opts, readpref.WithMaxStaleness(maxStaleness))
+	}
+
+	if tags == nil {
+		if maxStaleness == 0 {
+			return nil
+		}
+		return readpref.SecondaryPreferred(opts...)
+	}
+
+	return readpref.Nearest(append(opts, readpref.WithTagSets(tags))...)
+}
+
+func toTagSet(tags bson.D) (tag.Set, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("%w: at least one tag is required", ErrInvalidTagSet)
+	}
+
+	set := make(tag.Set, 0, len(tags))
+	for _, e := range tags {
+		value, ok := e.Value.(string)
+		if e.Key == "" || !ok {
+			return nil, fmt.Errorf("%w: tags must be non empty string keys with string values", ErrInvalidTagSet)
+		}
+		set = append(set, tag.Tag{Name: e.Key, Value: value})
+	}
+
+	return set, nil
+}
+
+// readCollection returns the collection reads must be run on, honouring the per operation read preference.
+func (mf *Model) readCollection(o findOptions) (*mongo.Collection, error) {
+	if o.readPrefTags == nil && o.maxStaleness == 0 {
+		return mf.col, nil
+	}
+
+	tags := o.readPrefTags
+	if tags == nil {
+		tags = mf.readPrefTags
+	}
+	maxStaleness := o.maxStaleness
+	if maxStaleness == 0 {
+		maxStaleness = mf.maxStaleness
+	}
+
+	return mf.col.Clone(options.Collection().SetReadPreference(buildReadPreference(tags, maxStaleness)))
+}
@@ -0,0 +1,238 @@
+package yamgo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	tDecimal = reflect.TypeOf(decimal.Decimal{})
+)
+
+// WithTypeRegistry sets the registry used to encode and decode the documents of the model.
+// The codecs of options like WithUUID take precedence over its own ones, without being registered on it.
+func WithTypeRegistry(registry *bsoncodec.Registry) Option {
+	return func(m *Model) error {
+		if registry == nil {
+			return errors.New("registry can't be nil")
+		}
+		m.registry = registry
+		return nil
+	}
+}
+
+// DefaultRegistry returns the driver default registry extended with codecs storing
+// uuid.UUID as BSON binary subtype 4 and decimal.Decimal as BSON Decimal128.
+// time.Time is stored as a BSON date, as in the driver default registry.
+func DefaultRegistry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+	registerUUIDCodec(registry, tUUID)
+
+	registry.RegisterTypeEncoder(tDecimal, bsoncodec.ValueEncoderFunc(encodeDecimal))
+	registry.RegisterTypeDecoder(tDecimal, bsoncodec.ValueDecoderFunc(decodeDecimal))
+
+	return registry
+}
+
+// buildRegistry combines the codecs of the model options with the registry set with WithTypeRegistry,
+// whatever their order. The codecs are registered on a registry of the model: the one set with WithTypeRegistry
+// is left untouched, since it may be shared with other models or be the driver default registry.
+func (mf *Model) buildRegistry() error {
+	if len(mf.registryCodecs) == 0 {
+		return nil
+	}
+
+	codecs := bsoncodec.NewRegistry()
+	if mf.registry == nil {
+		codecs = bson.NewRegistry()
+	}
+
+	for _, register := range mf.registryCodecs {
+		register(codecs)
+	}
+
+	if mf.registry == nil {
+		mf.registry = codecs
+		return nil
+	}
+
+	mf.registry = layerRegistry(codecs, mf.registry)
+	return nil
+}
+
+// plainStruct is a struct type no registry has a codec of its own for,
+// used to look up the codec a registry uses for structs.
+type plainStruct struct{}
+
+var tPlainStruct = reflect.TypeOf(plainStruct{})
+
+// layeredRegistry looks up the codecs of a type in the registry top, then in base.
+// The struct codec of top only replaces the one of base, not the codecs base has for given struct types like time.Time.
+type layeredRegistry struct {
+	top, base *bsoncodec.Registry
+	encoders  sync.Map // reflect.Type -> bsoncodec.ValueEncoder
+	decoders  sync.Map // reflect.Type -> bsoncodec.ValueDecoder
+}
+
+// layerRegistry returns a new registry using the codecs of top, then the ones of base.
+func layerRegistry(top, base *bsoncodec.Registry) *bsoncodec.Registry {
+	lr := &layeredRegistry{top: top, base: base}
+	registry := bsoncodec.NewRegistry()
+
+	// the type map entry of type 0 is the type of top level documents
+	for _, bt := range append([]bsontype.Type{0, bsontype.MinKey, bsontype.MaxKey}, bsonTypes()...) {
+		if t, err := base.LookupTypeMapEntry(bt); err == nil {
+			registry.RegisterTypeMapEntry(bt, t)
+		}
+	}
+
+	for kind := reflect.Bool; kind <= reflect.UnsafePointer; kind++ {
+		registry.RegisterKindEncoder(kind, bsoncodec.ValueEncoderFunc(lr.encodeValue))
+		registry.RegisterKindDecoder(kind, bsoncodec.ValueDecoderFunc(lr.decodeValue))
+	}
+
+	return registry
+}
+
+// bsonTypes returns the BSON types from double to decimal128.
+func bsonTypes() []bsontype.Type {
+	var types []bsontype.Type
+	for bt := bsontype.Double; bt <= bsontype.Decimal128; bt++ {
+		types = append(types, bt)
+	}
+	return types
+}
+
+func (lr *layeredRegistry) encodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	encoder, err := lr.lookupEncoder(val.Type())
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeValue(ec, vw, val)
+}
+
+func (lr *layeredRegistry) decodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	decoder, err := lr.lookupDecoder(val.Type())
+	if err != nil {
+		return err
+	}
+	return decoder.DecodeValue(dc, vr, val)
+}
+
+func (lr *layeredRegistry) lookupEncoder(t reflect.Type) (bsoncodec.ValueEncoder, error) {
+	if encoder, ok := lr.encoders.Load(t); ok {
+		return encoder.(bsoncodec.ValueEncoder), nil
+	}
+
+	encoder, err := lr.top.LookupEncoder(t)
+	if err != nil || !useTop(t, encoder, lr.top.LookupEncoder, lr.base.LookupEncoder) {
+		if encoder, err = lr.base.LookupEncoder(t); err != nil {
+			return nil, err
+		}
+	}
+
+	lr.encoders.Store(t, encoder)
+	return encoder, nil
+}
+
+func (lr *layeredRegistry) lookupDecoder(t reflect.Type) (bsoncodec.ValueDecoder, error) {
+	if decoder, ok := lr.decoders.Load(t); ok {
+		return decoder.(bsoncodec.ValueDecoder), nil
+	}
+
+	decoder, err := lr.top.LookupDecoder(t)
+	if err != nil || !useTop(t, decoder, lr.top.LookupDecoder, lr.base.LookupDecoder) {
+		if decoder, err = lr.base.LookupDecoder(t); err != nil {
+			return nil, err
+		}
+	}
+
+	lr.decoders.Store(t, decoder)
+	return decoder, nil
+}
+
+// useTop tells whether the codec found in the top registry for t is used: it is unless it's the struct codec
+// of the top registry and the base one has a codec of its own for t.
+func useTop[C any](t reflect.Type, codec C, lookupTop, lookupBase func(reflect.Type) (C, error)) bool {
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+
+	if topStruct, err := lookupTop(tPlainStruct); err != nil || !sameCodec(codec, topStruct) {
+		return true
+	}
+
+	baseCodec, err := lookupBase(t)
+	if err != nil {
+		return true
+	}
+	baseStruct, err := lookupBase(tPlainStruct)
+	return err == nil && sameCodec(baseCodec, baseStruct)
+}
+
+// sameCodec tells whether a and b are the same codec, codecs that can't be compared are never the same.
+func sameCodec[C any](a, b C) bool {
+	ta := reflect.TypeOf(a)
+	return ta != nil && ta == reflect.TypeOf(b) && ta.Comparable() && interface{}(a) == interface{}(b)
+}
+
+// bsonRegistry returns the registry the documents of the model are encoded with.
+func (mf *Model) bsonRegistry() *bsoncodec.Registry {
+	if mf.registry == nil {
+		return bson.DefaultRegistry
+	}
+	return mf.registry
+}
+
+func encodeDecimal(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tDecimal {
+		return bsoncodec.ValueEncoderError{Name: "DecimalEncodeValue", Types: []reflect.Type{tDecimal}, Received: val}
+	}
+
+	d128, err := primitive.ParseDecimal128(val.Interface().(decimal.Decimal).String())
+	if err != nil {
+		return err
+	}
+
+	return vw.WriteDecimal128(d128)
+}
+
+func decodeDecimal(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tDecimal {
+		return bsoncodec.ValueDecoderError{Name: "DecimalDecodeValue", Types: []reflect.Type{tDecimal}, Received: val}
+	}
+
+	switch vr.Type() {
+	case bsontype.Null:
+		val.Set(reflect.Zero(tDecimal))
+		return vr.ReadNull()
+	case bsontype.Undefined:
+		val.Set(reflect.Zero(tDecimal))
+		return vr.ReadUndefined()
+	case bsontype.Decimal128:
+	default:
+		return fmt.Errorf("cannot decode %v into a decimal", vr.Type())
+	}
+
+	d128, err := vr.ReadDecimal128()
+	if err != nil {
+		return err
+	}
+
+	d, err := decimal.NewFromString(d128.String())
+	if err != nil {
+		return err
+	}
+
+	val.Set(reflect.ValueOf(d))
+	return nil
+}
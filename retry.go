@@ -0,0 +1,59 @@
+package yamgo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RetryBackoff is the delay before the first retry of RetryRead, doubled after every attempt.
+var RetryBackoff = 100 * time.Millisecond
+
+// RetryRead calls fn until it succeeds, fails with an error other than a network error,
+// or has been retried maxRetries times. Only use it for idempotent reads: retrying a write
+// that reached the server before the connection dropped would apply it twice.
+func RetryRead(ctx context.Context, maxRetries int, fn func(ctx context.Context) error) error {
+	backoff := RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || !mongo.IsNetworkError(err) || attempt >= maxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// RetryableFind is Find retried on network errors, see RetryRead.
+func (mf *Model) RetryableFind(ctx context.Context, filter bson.M, results interface{}, maxRetries int) error {
+	return RetryRead(ctx, maxRetries, func(ctx context.Context) error {
+		return mf.find(ctx, filter, results)
+	})
+}
+
+// RetryableFindOne is FindOne retried on network errors, see RetryRead.
+func (mf *Model) RetryableFindOne(ctx context.Context, filter bson.M, result interface{}, maxRetries int) error {
+	return RetryRead(ctx, maxRetries, func(ctx context.Context) error {
+		return mf.findOne(ctx, filter, result)
+	})
+}
+
+// RetryableCount is CountDocuments retried on network errors, see RetryRead.
+func (mf *Model) RetryableCount(ctx context.Context, filter bson.M, maxRetries int) (int64, error) {
+	var count int64
+	err := RetryRead(ctx, maxRetries, func(ctx context.Context) (err error) {
+		count, err = mf.Count(ctx, filter)
+		return err
+	})
+	return count, err
+}
@@ -0,0 +1,142 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SearchScoreField is the field the Atlas Search relevance score is stored in when sorting by score.
+const SearchScoreField = "searchScore"
+
+type PaginationSearchParams struct {
+	PaginationFindParams
+	// SearchQuery is the body of the $search stage, e.g. {"index": "default", "text": {...}}.
+	SearchQuery bson.M
+	// SortByScore paginates on the search score, results must decode the searchScore field for the cursors.
+	SortByScore bool
+}
+
+// BuildSearchPipeline returns the aggregation PaginatedFindWithSearch runs for params:
+// $search, the cursor range $match, $sort and $limit. The lookup stages of the expansion are merged,
+// as on the models created without WithMergeAddFields(false).
+func BuildSearchPipeline(params PaginationSearchParams) (mongo.Pipeline, error) {
+	return buildSearchPipeline(params, true)
+}
+
+// buildSearchPipeline works like BuildSearchPipeline, merging the lookup stages of the expansion with mergeLookups.
+func buildSearchPipeline(params PaginationSearchParams, mergeLookups bool) (mongo.Pipeline, error) {
+
+	if len(params.SearchQuery) == 0 {
+		return nil, errors.New("search query can't be empty")
+	}
+
+	findParams := searchFindParams(params)
+
+	queries, sort, err := BuildQueries(findParams)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$search", Value: params.SearchQuery}}}
+
+	if params.SortByScore {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{SearchScoreField: bson.M{"$meta": "searchScore"}}}})
+	}
+
+	pipeline = append(pipeline,
+		bson.D{{Key: "$match", Value: bson.M{"$and": queries}}},
+		bson.D{{Key: "$sort", Value: sort}},
+		bson.D{{Key: "$limit", Value: findParams.Limit + 1}},
+	)
+
+	if findParams.Projection != "" {
+		projection, err := ParseProjection(findParams.Projection)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: projection}})
+	}
+
+	pipeline = append(pipeline, BuildLookupStages(findParams.Expansion, mergeLookups)...)
+
+	return pipeline, nil
+}
+
+func searchFindParams(params PaginationSearchParams) PaginationFindParams {
+	findParams := params.PaginationFindParams
+	if params.SortByScore {
+		findParams.PaginatedField = SearchScoreField
+	}
+	if findParams.Query == nil {
+		findParams.Query = bson.M{}
+	}
+	return ensureMandatoryParams(findParams)
+}
+
+// PaginatedFindWithSearch works like PaginatedFind but selects the documents with an Atlas Search query.
+func (mf *Model) PaginatedFindWithSearch(ctx context.Context, params PaginationSearchParams, results interface{}) (Page, error) {
+
+	if results == nil {
+		return Page{}, errors.New("results can't be nil")
+	}
+
+	params.Query = mf.interceptQuery(params.Query)
+
+	pipeline, err := buildSearchPipeline(params, !mf.separateAddFields)
+	if err != nil {
+		return Page{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("PaginatedFindWithSearch", params.Query, time.Now())
+
+	var count int
+	if params.CountTotal {
+		if count, err = mf.searchCount(ctx, params); err != nil {
+			return Page{}, err
+		}
+	}
+
+	cur, err := mf.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return Page{}, err
+	}
+
+	if err = mf.decodeAll(ctx, cur, results); err != nil {
+		return Page{}, err
+	}
+
+	return buildPage(searchFindParams(params), count, results)
+}
+
+// searchCount counts the documents matching the search query and params.Query.
+func (mf *Model) searchCount(ctx context.Context, params PaginationSearchParams) (int, error) {
+
+	pipeline := mongo.Pipeline{{{Key: "$search", Value: params.SearchQuery}}}
+	if len(params.Query) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: params.Query}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$count", Value: "count"}})
+
+	cur, err := mf.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	var counts []struct {
+		Count int `bson:"count"`
+	}
+	if err = cur.All(ctx, &counts); err != nil {
+		return 0, err
+	}
+
+	if len(counts) == 0 {
+		return 0, nil
+	}
+	return counts[0].Count, nil
+}
@@ -0,0 +1,71 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WithSeedData inserts docs when the model is created, provided the collection is empty.
+func WithSeedData(docs []interface{}) Option {
+	return func(m *Model) error {
+		m.seedData = docs
+		return nil
+	}
+}
+
+// WithSeedDataIfEmpty is an alias of WithSeedData making explicit that a non-empty collection is left untouched.
+func WithSeedDataIfEmpty(docs []interface{}) Option {
+	return WithSeedData(docs)
+}
+
+// Seed inserts the documents registered with WithSeedData if the collection is empty.
+// Calling it on a non-empty collection is a no-op.
+//
+// The documents are inserted with a single unordered InsertMany, which isn't atomic. Duplicate key errors are
+// ignored, so that concurrent Seeds of documents with a fixed _id insert each of them once. Any other failure
+// may leave the collection partially seeded, and no longer empty: clear it before calling Seed again.
+func (mf *Model) Seed(ctx context.Context) error {
+	if len(mf.seedData) == 0 {
+		return nil
+	}
+
+	countCtx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+
+	count, err := mf.col.CountDocuments(countCtx, bson.M{}, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	_, err = mf.insertMany(ctx, mf.seedData, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeyErrors(err) {
+		return err
+	}
+
+	return nil
+}
+
+// onlyDuplicateKeyErrors reports whether err is a bulk write failure caused by unique index violations only.
+func onlyDuplicateKeyErrors(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) == 0 {
+		return false
+	}
+
+	for _, we := range bwe.WriteErrors {
+		if !mongo.IsDuplicateKeyError(we.WriteError) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,215 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+var (
+	ErrServerUnavailable = errors.New("server unavailable")
+	ErrInvalidOption     = errors.New("invalid option")
+)
+
+// minHeartbeatInterval is the smallest heartbeat interval MongoDB recommends.
+const minHeartbeatInterval = 500 * time.Millisecond
+
+// ServerUnavailableError is returned when no server could be selected in time.
+// It matches ErrServerUnavailable with errors.Is.
+type ServerUnavailableError struct {
+	// After is how long the server was waited for, zero when unknown, e.g. for the model operations.
+	After time.Duration
+	Err   error
+}
+
+func (e *ServerUnavailableError) Error() string {
+	if e.After == 0 {
+		return fmt.Sprintf("yamgo: could not reach MongoDB, check connection: %s", e.Err)
+	}
+	return fmt.Sprintf("yamgo: could not reach MongoDB after %s, check connection: %s", e.After, e.Err)
+}
+
+func (e *ServerUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ServerUnavailableError) Is(target error) bool {
+	return target == ErrServerUnavailable
+}
+
+// WithServerSelectionTimeout sets how long an operation waits for a suitable server before failing.
+func WithServerSelectionTimeout(d time.Duration) ClientOption {
+	return func(o *options.ClientOptions) error {
+		if d <= 0 {
+			return errors.New("server selection timeout must be greater than zero")
+		}
+		o.SetServerSelectionTimeout(d)
+		return nil
+	}
+}
+
+// WithSocketTimeout sets how long a read or write on a connection may block before failing, 0 never times out.
+// Raise it for long-running analytical queries.
+func WithSocketTimeout(d time.Duration) ClientOption {
+	return func(o *options.ClientOptions) error {
+		if d < 0 {
+			return fmt.Errorf("%w: socket timeout %s can't be negative", ErrInvalidOption, d)
+		}
+		o.SetSocketTimeout(d)
+		return nil
+	}
+}
+
+// WithHeartbeatInterval sets how often the driver checks the state of the servers, at least 500ms.
+func WithHeartbeatInterval(d time.Duration) ClientOption {
+	return func(o *options.ClientOptions) error {
+		if d < minHeartbeatInterval {
+			return fmt.Errorf("%w: heartbeat interval %s is less than %s", ErrInvalidOption, d, minHeartbeatInterval)
+		}
+		o.SetHeartbeatInterval(d)
+		return nil
+	}
+}
+
+// Ping checks that the server the package is connected to is reachable.
+func Ping(ctx context.Context) error {
+	return PingClient(ctx, _mongo.client)
+}
+
+// PingClient checks that client can reach a server, returning a ServerUnavailableError if it can't.
+func PingClient(ctx context.Context, client *mongo.Client) error {
+	start := time.Now()
+
+	return serverUnavailable(client.Ping(ctx, nil), start)
+}
+
+// serverUnavailable converts server selection failures of an operation started at start into ServerUnavailableError.
+// A zero start leaves the wait unknown.
+func serverUnavailable(err error, start time.Time) error {
+	var selectionErr topology.ServerSelectionError
+	if !errors.As(err, &selectionErr) {
+		return err
+	}
+
+	unavailable := &ServerUnavailableError{Err: err}
+	if !start.IsZero() {
+		unavailable.After = time.Since(start).Round(time.Millisecond)
+	}
+	return unavailable
+}
+
+// documentValidationFailure is the server error code of a write rejected by the collection validator.
+const documentValidationFailure = 121
+
+// ValidationCheck is the outcome of one step of ValidateConnection.
+type ValidationCheck struct {
+	OK      bool
+	Latency time.Duration
+	// Detail explains a failed check, e.g. "write-blocked by schema".
+	Detail string
+	Err    error
+}
+
+// ValidationReport is the outcome of ValidateConnection. A step is not run when a previous one failed.
+type ValidationReport struct {
+	Ping   ValidationCheck
+	Write  ValidationCheck
+	Read   ValidationCheck
+	Delete ValidationCheck
+}
+
+// OK reports whether every check succeeded.
+func (r ValidationReport) OK() bool {
+	return r.Ping.OK && r.Write.OK && r.Read.OK && r.Delete.OK
+}
+
+// err returns an error describing the first failed check, nil if every check succeeded.
+func (r ValidationReport) err() error {
+	for _, check := range []struct {
+		name string
+		ValidationCheck
+	}{{"ping", r.Ping}, {"write", r.Write}, {"read", r.Read}, {"delete", r.Delete}} {
+		if !check.OK {
+			if check.Detail == "" {
+				return fmt.Errorf("%s check failed", check.name)
+			}
+			return fmt.Errorf("%s check failed: %s", check.name, check.Detail)
+		}
+	}
+	return nil
+}
+
+// ValidateConnection checks that the collection of the model can be written and read, not only that the server is reachable:
+// it pings the server, inserts a probe document, finds it and deletes it. The probe is marked with a yamgoConnectionProbe
+// field, so it can be found and removed if the delete fails.
+func (mf *Model) ValidateConnection(ctx context.Context) ValidationReport {
+	var report ValidationReport
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+
+	report.Ping = validationCheck(func() error {
+		return PingClient(ctx, mf.col.Database().Client())
+	})
+	if !report.Ping.OK {
+		return report
+	}
+
+	id := primitive.NewObjectID()
+	probe := bson.M{"_id": id, "yamgoConnectionProbe": true}
+
+	report.Write = validationCheck(func() error {
+		_, err := mf.col.InsertOne(ctx, probe)
+		return err
+	})
+	if !report.Write.OK {
+		if isDocumentValidationFailure(report.Write.Err) {
+			report.Write.Detail = "write-blocked by schema"
+		}
+		return report
+	}
+
+	report.Read = validationCheck(func() error {
+		return mf.col.FindOne(ctx, bson.M{"_id": id}).Err()
+	})
+
+	report.Delete = validationCheck(func() error {
+		res, err := mf.col.DeleteOne(ctx, bson.M{"_id": id})
+		if err == nil && res.DeletedCount != 1 {
+			return mongo.ErrNoDocuments
+		}
+		return err
+	})
+
+	return report
+}
+
+func validationCheck(fn func() error) ValidationCheck {
+	start := time.Now()
+	err := fn()
+	check := ValidationCheck{OK: err == nil, Latency: time.Since(start), Err: err}
+	if err != nil {
+		check.Detail = err.Error()
+	}
+	return check
+}
+
+func isDocumentValidationFailure(err error) bool {
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) {
+		return false
+	}
+	for _, we := range writeErr.WriteErrors {
+		if we.Code == documentValidationFailure {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package yamgo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithSession returns a context running the yamgo operations it is passed to in session,
+// e.g. inside a transaction started by the caller. Only the methods taking a context use the session,
+// so use the Context variants of the methods, e.g. FindContext or AggregateContext, and Count
+// instead of their context free equivalents. Transactions require a replica set or a sharded cluster.
+func WithSession(ctx context.Context, session mongo.Session) context.Context {
+	return mongo.NewSessionContext(ctx, session)
+}
+
+// hasSession reports whether ctx carries a session, which can't be used concurrently.
+func hasSession(ctx context.Context) bool {
+	return mongo.SessionFromContext(ctx) != nil
+}
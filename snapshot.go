@@ -0,0 +1,33 @@
+package yamgo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Snapshot runs fn in a snapshot session so that every read done with snapshotCtx,
+// on any collection, sees the data as it was at the first read of the session.
+// Only the methods taking a context use the session. Snapshot reads require a replica set or a sharded cluster.
+func (mf *Model) Snapshot(ctx context.Context, fn func(snapshotCtx context.Context) error) error {
+
+	session, err := mf.NativeClient().StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		return fn(sc)
+	})
+}
+
+// FindWithSnapshot works like Find but reads with snapshot read concern, so that documents written while
+// the cursor is iterated are not returned. It requires a replica set or a sharded cluster.
+func (mf *Model) FindWithSnapshot(ctx context.Context, filter bson.M, results interface{}) error {
+	return mf.Snapshot(ctx, func(snapshotCtx context.Context) error {
+		return mf.find(snapshotCtx, filter, results)
+	})
+}
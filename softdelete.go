@@ -0,0 +1,77 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// WithSoftDelete marks documents as deleted by setting field to the deletion time instead of removing them.
+// Find, FindOne, CountDocuments and PaginatedFind then skip the documents having field, unless
+// IncludeDeleted is passed or PaginationFindParams.IncludeDeleted is set.
+func WithSoftDelete(field string) Option {
+	return func(m *Model) error {
+		if field == "" {
+			return errors.New("soft delete field can't be empty")
+		}
+		m.softDeleteField = field
+		return nil
+	}
+}
+
+// IncludeDeleted disables the soft delete scope for the operation, so deleted documents are returned too.
+func IncludeDeleted() FindOption {
+	return func(o *findOptions) error {
+		o.includeDeleted = true
+		return nil
+	}
+}
+
+// ScopeOption configures the query scopes of a single read operation.
+type ScopeOption = FindOption
+
+// WithDeletedScope is IncludeDeleted as a ScopeOption, so admin code can see the deleted documents.
+func WithDeletedScope() ScopeOption {
+	return IncludeDeleted()
+}
+
+// SoftDelete marks the documents matching filter as deleted and returns how many were marked.
+// Documents already deleted keep their original deletion time.
+func (mf *Model) SoftDelete(ctx context.Context, filter bson.M) (int64, error) {
+	if mf.softDeleteField == "" {
+		return 0, errors.New("soft delete isn't enabled on the model")
+	}
+
+	filter = mf.scopeDeleted(mf.interceptQuery(filter), false)
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("SoftDelete", filter, time.Now())
+
+	res, err := mf.col.UpdateMany(ctx, filter, bson.M{"$set": bson.M{mf.softDeleteField: time.Now()}})
+	if err != nil {
+		return 0, mf.wrapQueryError("SoftDelete", filter, err)
+	}
+
+	return res.ModifiedCount, nil
+}
+
+// scopeDeleted adds the soft delete condition to filter, unless the caller already filters on the field.
+func (mf *Model) scopeDeleted(filter bson.M, includeDeleted bool) bson.M {
+	if mf.softDeleteField == "" || includeDeleted {
+		return filter
+	}
+	if _, ok := filter[mf.softDeleteField]; ok {
+		return filter
+	}
+
+	f := make(bson.M, len(filter)+1)
+	for key, value := range filter {
+		f[key] = value
+	}
+	f[mf.softDeleteField] = bson.M{"$exists": false}
+
+	return f
+}
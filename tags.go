@@ -0,0 +1,44 @@
+package yamgo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+// WithBSONTagName reads the BSON field names of structs from the tag struct tag, e.g. "json", instead of "bson".
+// The tag value follows the bson tag syntax: a name, "-" to skip the field, and options like omitempty.
+// Other models sharing the registry set with WithTypeRegistry keep reading the bson tag.
+func WithBSONTagName(tag string) Option {
+	return func(m *Model) error {
+		if tag == "" {
+			return errors.New("tag name can't be empty")
+		}
+
+		codec, err := bsoncodec.NewStructCodec(tagParser(tag))
+		if err != nil {
+			return err
+		}
+
+		m.registryCodecs = append(m.registryCodecs, func(registry *bsoncodec.Registry) {
+			registry.RegisterKindEncoder(reflect.Struct, codec)
+			registry.RegisterKindDecoder(reflect.Struct, codec)
+		})
+		return nil
+	}
+}
+
+// tagParser parses the tag struct tag as if it was the bson one.
+func tagParser(tag string) bsoncodec.StructTagParserFunc {
+	return func(sf reflect.StructField) (bsoncodec.StructTags, error) {
+		value, ok := sf.Tag.Lookup(tag)
+		if !ok {
+			return bsoncodec.DefaultStructTagParser(reflect.StructField{Name: sf.Name})
+		}
+
+		sf.Tag = reflect.StructTag(fmt.Sprintf("bson:%q", value))
+		return bsoncodec.DefaultStructTagParser(sf)
+	}
+}
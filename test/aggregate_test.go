@@ -0,0 +1,285 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type facetCount struct {
+	ID    string `bson:"_id"`
+	Count int    `bson:"count"`
+}
+
+func insertOrders(t *testing.T) yamgo.Model {
+	orderModel := yamgo.NewModel("orders")
+
+	_, err := orderModel.InsertMany([]interface{}{
+		bson.M{"status": "open", "category": "books", "amount": 10},
+		bson.M{"status": "open", "category": "games", "amount": 20},
+		bson.M{"status": "closed", "category": "books", "amount": 30},
+		bson.M{"status": "closed", "category": "books", "amount": 40},
+		bson.M{"status": "cancelled", "category": "music", "amount": 50},
+	})
+	assert.Nil(t, err)
+
+	return orderModel
+}
+
+func countBy(field string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$" + field}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+}
+
+func TestFacet(t *testing.T) {
+	orderModel := insertOrders(t)
+
+	facets := map[string]mongo.Pipeline{
+		"byStatus":   countBy("status"),
+		"byCategory": countBy("category"),
+	}
+
+	raws, err := orderModel.Facet(context.TODO(), bson.M{}, facets)
+
+	assert.Nil(t, err)
+	assert.Len(t, raws["byStatus"], 3)
+	assert.Len(t, raws["byCategory"], 3)
+
+	results, err := yamgo.FacetT[facetCount](context.TODO(), &orderModel, bson.M{"status": bson.M{"$ne": "cancelled"}}, facets)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []facetCount{{ID: "closed", Count: 2}, {ID: "open", Count: 2}}, results["byStatus"])
+	assert.Equal(t, []facetCount{{ID: "books", Count: 3}, {ID: "games", Count: 1}}, results["byCategory"])
+
+	DropCollection("orders")
+}
+
+func TestGraphLookup(t *testing.T) {
+	categoryModel := yamgo.NewModel("categories")
+
+	_, err := categoryModel.InsertMany([]interface{}{
+		bson.M{"_id": "root"},
+		bson.M{"_id": "level1", "parent": "root"},
+		bson.M{"_id": "level2", "parent": "level1"},
+		bson.M{"_id": "level3", "parent": "level2"},
+		bson.M{"_id": "level4", "parent": "level3"},
+		bson.M{"_id": "other"},
+	})
+	assert.Nil(t, err)
+
+	maxDepth := 2
+	depthField := "depth"
+
+	results := []struct {
+		ID          string `bson:"_id"`
+		Descendants []struct {
+			ID    string `bson:"_id"`
+			Depth int    `bson:"depth"`
+		} `bson:"descendants"`
+	}{}
+
+	err = categoryModel.GraphLookup(context.TODO(), yamgo.GraphLookupParams{
+		From:             "categories",
+		StartWith:        "$_id",
+		ConnectFromField: "_id",
+		ConnectToField:   "parent",
+		As:               "descendants",
+		MaxDepth:         &maxDepth,
+		DepthField:       &depthField,
+		Filter:           bson.M{"_id": "root"},
+	}, &results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+
+	depths := map[string]int{}
+	for _, descendant := range results[0].Descendants {
+		depths[descendant.ID] = descendant.Depth
+	}
+
+	assert.Equal(t, map[string]int{"level1": 0, "level2": 1, "level3": 2}, depths)
+
+	err = categoryModel.GraphLookup(context.TODO(), yamgo.GraphLookupParams{From: "categories"}, &results)
+	assert.Error(t, err)
+
+	DropCollection("categories")
+}
+
+func TestDateTruncAggregate(t *testing.T) {
+	eventModel := yamgo.NewModel("events")
+	day := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := eventModel.InsertMany([]interface{}{
+		bson.M{"createdAt": day.Add(1 * time.Hour), "amount": 1},
+		bson.M{"createdAt": day.Add(2 * time.Hour), "amount": 2},
+		bson.M{"createdAt": day.Add(25 * time.Hour), "amount": 3},
+		bson.M{"createdAt": day.Add(49 * time.Hour), "amount": 4},
+		bson.M{"createdAt": day.Add(50 * time.Hour), "amount": 5},
+	})
+	assert.Nil(t, err)
+
+	buckets, err := eventModel.DateTruncAggregate(context.TODO(), "createdAt", "day", bson.M{}, bson.D{{Key: "total", Value: bson.D{{Key: "$sum", Value: "$amount"}}}})
+
+	assert.Nil(t, err)
+	assert.Len(t, buckets, 3)
+
+	assert.True(t, day.Equal(buckets[0].Period))
+	assert.True(t, day.Add(24*time.Hour).Equal(buckets[1].Period))
+	assert.True(t, day.Add(48*time.Hour).Equal(buckets[2].Period))
+
+	assert.Equal(t, []int64{2, 1, 2}, []int64{buckets[0].Count, buckets[1].Count, buckets[2].Count})
+	assert.EqualValues(t, 9, buckets[2].Fields["total"])
+
+	_, err = eventModel.DateTruncAggregate(context.TODO(), "createdAt", "fortnight", bson.M{}, nil)
+	assert.Error(t, err)
+
+	DropCollection("events")
+}
+
+func TestFindUnwound(t *testing.T) {
+	postModel := yamgo.NewModel("posts")
+
+	_, err := postModel.InsertMany([]interface{}{
+		bson.M{"_id": 1, "tags": bson.A{"go", "mongo"}},
+		bson.M{"_id": 2, "tags": bson.A{"go"}},
+		bson.M{"_id": 3, "tags": bson.A{}},
+	})
+	assert.Nil(t, err)
+
+	results := []struct {
+		ID        int    `bson:"_id"`
+		Tags      string `bson:"tags"`
+		TagsIndex int64  `bson:"tagsIndex"`
+	}{}
+
+	err = postModel.FindUnwound(context.TODO(), bson.M{}, "tags", false, true, &results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "go", results[0].Tags)
+	assert.Equal(t, "mongo", results[1].Tags)
+	assert.Equal(t, int64(1), results[1].TagsIndex)
+
+	preserved := []bson.M{}
+	err = postModel.FindUnwound(context.TODO(), bson.M{}, "tags", true, false, &preserved)
+
+	assert.Nil(t, err)
+	assert.Len(t, preserved, 4)
+
+	groups, err := postModel.UnwindAndGroup(context.TODO(), bson.M{}, "tags", "tags", bson.M{"count": bson.M{"$sum": 1}})
+
+	assert.Nil(t, err)
+
+	counts := map[string]int32{}
+	for _, group := range groups {
+		counts[group["_id"].(string)] = group["count"].(int32)
+	}
+	assert.Equal(t, map[string]int32{"go": 2, "mongo": 1}, counts)
+
+	DropCollection("posts")
+}
+
+func TestAggregateInto(t *testing.T) {
+	orderModel := insertOrders(t)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$category"}, {Key: "total", Value: bson.D{{Key: "$sum", Value: "$amount"}}}}}},
+	}
+
+	err := orderModel.AggregateInto(context.TODO(), bson.M{"status": bson.M{"$ne": "cancelled"}}, pipeline, "summary", yamgo.MergeOptions{
+		On:             []string{"_id"},
+		WhenMatched:    "replace",
+		WhenNotMatched: "insert",
+	})
+	assert.Nil(t, err)
+
+	summaryModel := yamgo.NewModel("summary")
+	results := []struct {
+		ID    string `bson:"_id"`
+		Total int    `bson:"total"`
+	}{}
+
+	err = summaryModel.Find(bson.M{}, &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+
+	totals := map[string]int{}
+	for _, res := range results {
+		totals[res.ID] = res.Total
+	}
+	assert.Equal(t, map[string]int{"books": 80, "games": 20}, totals)
+
+	err = orderModel.AggregateInto(context.TODO(), bson.M{}, pipeline, "summary", yamgo.MergeOptions{WhenMatched: "overwrite"})
+	assert.Error(t, err)
+
+	DropCollection("orders")
+	DropCollection("summary")
+}
+
+func TestRawAggregate(t *testing.T) {
+	salesModel := yamgo.NewModel("sales")
+
+	_, err := salesModel.InsertMany([]interface{}{
+		bson.M{"region": "eu", "amount": 10},
+		bson.M{"region": "eu", "amount": 5},
+		bson.M{"region": "us", "amount": 7},
+	})
+	assert.Nil(t, err)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$region", "total": bson.M{"$sum": "$amount"}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	raws, err := salesModel.RawAggregate(context.TODO(), pipeline)
+	assert.Nil(t, err)
+	assert.Len(t, raws, 2)
+	assert.Equal(t, "eu", raws[0].Lookup("_id").StringValue())
+	assert.Equal(t, int32(15), raws[0].Lookup("total").Int32())
+
+	cur, err := salesModel.RawAggregateIter(context.TODO(), pipeline)
+	assert.Nil(t, err)
+
+	totals := map[string]int32{}
+	for cur.Next(context.TODO()) {
+		totals[cur.Current.Lookup("_id").StringValue()] = cur.Current.Lookup("total").Int32()
+	}
+	assert.Nil(t, cur.Err())
+	assert.Nil(t, cur.Close(context.TODO()))
+	assert.Equal(t, map[string]int32{"eu": 15, "us": 7}, totals)
+
+	DropCollection("sales")
+}
+
+func TestAggregateWithOptions(t *testing.T) {
+	orderModel := insertOrders(t)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$gte": bson.A{"$amount", "$$minAmount"}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "amount", Value: 1}}}},
+	}
+
+	var results []bson.M
+	err := orderModel.AggregateWithOptions(context.TODO(), pipeline, yamgo.AggregateOptions{
+		AllowDiskUse: true,
+		BatchSize:    2,
+		MaxTime:      5 * time.Second,
+		Comment:      "aggregate-options-test",
+		Let:          bson.M{"minAmount": 30},
+	}, &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 3)
+	assert.EqualValues(t, 30, results[0]["amount"])
+
+	err = orderModel.AggregateWithOptions(context.TODO(), pipeline, yamgo.AggregateOptions{}, &results)
+	assert.NotNil(t, err)
+
+	DropCollection("orders")
+}
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAutoCreateIndexesWarnOnly(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	itemModel := yamgo.NewModel("items",
+		yamgo.WithSlowQueryLog(time.Hour, logger),
+		yamgo.WithAutoCreateIndexes(yamgo.AutoIndexWarnOnly),
+	)
+
+	_, err := itemModel.InsertMany([]interface{}{bson.M{"name": "foo"}, bson.M{"name": "bar"}})
+	assert.Nil(t, err)
+
+	results := []bson.M{}
+	err = itemModel.Find(bson.M{"name": "foo"}, &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+
+	entry := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "yamgo: collection scan", entry["msg"])
+	assert.Equal(t, "Find", entry["op"])
+	assert.Equal(t, []interface{}{"name"}, entry["fields"])
+
+	buf.Reset()
+
+	// the same filter shape is only analyzed once
+	err = itemModel.Find(bson.M{"name": "bar"}, &results)
+	assert.Nil(t, err)
+	assert.Empty(t, buf.String())
+
+	indexes, err := itemModel.NativeCollection().Indexes().ListSpecifications(context.TODO())
+	assert.Nil(t, err)
+	assert.Len(t, indexes, 1)
+
+	DropCollection("items")
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestBatch(t *testing.T) {
+	itemModel := yamgo.NewModel("items")
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	err := itemModel.Batch(context.TODO(), func(b yamgo.BatchContext) error {
+		if _, err := b.InsertOne(&item); err != nil {
+			return err
+		}
+
+		var found models.ItemSchema
+		if err := b.FindByID(item.ID.Hex(), &found); err != nil {
+			return err
+		}
+		assert.Equal(t, item.ID, found.ID)
+
+		count, err := b.CountDocuments(nil)
+		assert.Equal(t, 1, count)
+		if err != nil {
+			return err
+		}
+
+		var page []models.ItemSchema
+		if _, err := b.PaginatedFind(yamgo.PaginationFindParams{Query: bson.M{}, Limit: 10}, &page); err != nil {
+			return err
+		}
+		assert.Len(t, page, 1)
+
+		var grouped []bson.M
+		if err := b.Aggregate(mongo.Pipeline{{{Key: "$count", Value: "total"}}}, &grouped); err != nil {
+			return err
+		}
+		assert.Equal(t, []bson.M{{"total": int32(1)}}, grouped)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	DropCollection("items")
+}
+
+func TestBatchTimeout(t *testing.T) {
+	itemModel := yamgo.NewModel("items", yamgo.WithBatchTimeout(200*time.Millisecond))
+
+	_, err := itemModel.InsertOne(&models.ItemSchema{ID: primitive.NewObjectID()})
+	assert.Nil(t, err)
+
+	var insertErr error
+	err = itemModel.Batch(context.TODO(), func(b yamgo.BatchContext) error {
+		var item models.ItemSchema
+		_ = b.FindOne(bson.M{"$where": "sleep(500) || true"}, &item)
+
+		start := time.Now()
+		_, insertErr = b.InsertOne(&models.ItemSchema{ID: primitive.NewObjectID()})
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+		return insertErr
+	})
+
+	assert.True(t, errors.Is(insertErr, context.DeadlineExceeded))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	count, err := itemModel.CountDocuments(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	DropCollection("items")
+}
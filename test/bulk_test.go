@@ -0,0 +1,71 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestBulkWriteReport(t *testing.T) {
+	itemModel := models.ItemModel()
+	ctx := context.Background()
+
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	writes := []mongo.WriteModel{
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: first}),
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: first}),
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: second}),
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: second}),
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: primitive.NewObjectID()}),
+	}
+
+	report, err := itemModel.BulkWrite(ctx, writes, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, report.Succeeded())
+	assert.Equal(t, 2, report.Failed())
+	assert.Equal(t, int64(3), report.Result.InsertedCount)
+
+	assert.Equal(t, 1, report.WriteErrors[0].Index)
+	assert.Equal(t, 3, report.WriteErrors[1].Index)
+	assert.Equal(t, models.ItemSchema{ID: second}, report.WriteErrors[1].Document)
+	for _, bwe := range report.WriteErrors {
+		assert.True(t, yamgo.IsDuplicateKeyError(bwe))
+	}
+
+	count, err := itemModel.CountDocuments(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	DropCollection("items")
+}
+
+func TestBulkWriteReportOrdered(t *testing.T) {
+	itemModel := models.ItemModel()
+	ctx := context.Background()
+
+	id := primitive.NewObjectID()
+	writes := []mongo.WriteModel{
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: id}),
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: id}),
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: primitive.NewObjectID()}),
+	}
+
+	report, err := itemModel.BulkWrite(ctx, writes, true)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, report.Succeeded())
+	assert.Equal(t, 1, report.Failed())
+
+	report, err = itemModel.BulkWrite(ctx, []mongo.WriteModel{
+		mongo.NewInsertOneModel().SetDocument(models.ItemSchema{ID: primitive.NewObjectID()}),
+	}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Succeeded())
+	assert.Equal(t, 0, report.Failed())
+
+	DropCollection("items")
+}
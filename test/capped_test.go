@@ -0,0 +1,68 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type queueEntry struct {
+	Seq int `bson:"seq"`
+}
+
+func TestFindWithNaturalOrder(t *testing.T) {
+	err := yamgo.GetDB().Database.CreateCollection(context.TODO(), "queue", options.CreateCollection().SetCapped(true).SetSizeInBytes(4096))
+	assert.Nil(t, err)
+
+	queueModel := yamgo.NewModel("queue")
+
+	_, err = queueModel.InsertMany([]interface{}{queueEntry{Seq: 3}, queueEntry{Seq: 1}, queueEntry{Seq: 2}})
+	assert.Nil(t, err)
+
+	entries := []queueEntry{}
+	err = queueModel.FindWithNaturalOrder(context.TODO(), nil, false, &entries)
+	assert.Nil(t, err)
+	assert.Equal(t, []queueEntry{{Seq: 3}, {Seq: 1}, {Seq: 2}}, entries)
+
+	err = queueModel.FindWithNaturalOrder(context.TODO(), bson.M{}, true, &entries)
+	assert.Nil(t, err)
+	assert.Equal(t, []queueEntry{{Seq: 2}, {Seq: 1}, {Seq: 3}}, entries)
+
+	DropCollection("queue")
+}
+
+func TestTailableFollowNatural(t *testing.T) {
+	err := yamgo.GetDB().Database.CreateCollection(context.TODO(), "queue", options.CreateCollection().SetCapped(true).SetSizeInBytes(4096))
+	assert.Nil(t, err)
+
+	queueModel := yamgo.NewModel("queue")
+
+	_, err = queueModel.InsertOne(queueEntry{Seq: 1})
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := queueModel.TailableFollowNatural(ctx, nil)
+	assert.Nil(t, err)
+	defer cur.Close(context.TODO())
+
+	var entry queueEntry
+	assert.True(t, cur.Next(ctx))
+	assert.Nil(t, cur.Decode(&entry))
+	assert.Equal(t, 1, entry.Seq)
+
+	_, err = queueModel.InsertOne(queueEntry{Seq: 2})
+	assert.Nil(t, err)
+
+	assert.True(t, cur.Next(ctx))
+	assert.Nil(t, cur.Decode(&entry))
+	assert.Equal(t, 2, entry.Seq)
+
+	DropCollection("queue")
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type job struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty"`
+	Worker  string             `bson:"worker,omitempty"`
+	Payload int                `bson:"payload"`
+}
+
+func TestFindWithClaim(t *testing.T) {
+	jobModel := yamgo.NewModel("jobs")
+
+	docs := []interface{}{}
+	for i := 0; i < 5; i++ {
+		docs = append(docs, job{Payload: i})
+	}
+	_, err := jobModel.InsertMany(docs)
+	assert.Nil(t, err)
+
+	var mu sync.Mutex
+	claimedBy := map[primitive.ObjectID][]string{}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 10; w++ {
+		worker := fmt.Sprintf("worker-%d", w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var claimed job
+				ok, err := jobModel.FindWithClaim(context.TODO(), bson.M{}, "worker", worker, time.Minute, &claimed)
+				assert.Nil(t, err)
+				if !ok {
+					return
+				}
+				assert.Equal(t, worker, claimed.Worker)
+
+				mu.Lock()
+				claimedBy[claimed.ID] = append(claimedBy[claimed.ID], worker)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, claimedBy, 5)
+	for id, workers := range claimedBy {
+		assert.Len(t, workers, 1, "job %s claimed by %v", id.Hex(), workers)
+	}
+
+	DropCollection("jobs")
+}
+
+func TestReleaseClaim(t *testing.T) {
+	jobModel := yamgo.NewModel("jobs")
+
+	_, err := jobModel.InsertOne(job{Payload: 1})
+	assert.Nil(t, err)
+
+	var claimed job
+	ok, err := jobModel.FindWithClaim(context.TODO(), nil, "worker", "a", time.Minute, &claimed)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = jobModel.FindWithClaim(context.TODO(), nil, "worker", "b", time.Minute, &claimed)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	err = jobModel.ReleaseClaim(context.TODO(), bson.M{"_id": claimed.ID}, "worker")
+	assert.Nil(t, err)
+
+	ok, err = jobModel.FindWithClaim(context.TODO(), nil, "worker", "b", time.Minute, &claimed)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "b", claimed.Worker)
+
+	DropCollection("jobs")
+}
+
+func TestFindWithClaimExpired(t *testing.T) {
+	jobModel := yamgo.NewModel("jobs")
+
+	_, err := jobModel.InsertOne(job{Payload: 1})
+	assert.Nil(t, err)
+
+	var claimed job
+	ok, err := jobModel.FindWithClaim(context.TODO(), nil, "worker", "a", -time.Second, &claimed)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = jobModel.FindWithClaim(context.TODO(), nil, "worker", "b", time.Minute, &claimed)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "b", claimed.Worker)
+
+	DropCollection("jobs")
+}
@@ -0,0 +1,38 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRenameCollection(t *testing.T) {
+	model := yamgo.NewModel("products_v1")
+
+	_, err := model.InsertOne(bson.M{"name": "coffee"})
+	assert.Nil(t, err)
+
+	err = model.RenameCollection(context.TODO(), "products_v2", false)
+	assert.Nil(t, err)
+	assert.Equal(t, "products_v2", model.CollectionName())
+
+	exists, err := yamgo.CollectionExists(context.TODO(), "products_v1")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	exists, err = yamgo.CollectionExists(context.TODO(), "products_v2")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	_, err = model.InsertOne(bson.M{"name": "tea"})
+	assert.Nil(t, err)
+
+	count, err := model.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	DropCollection("products_v2")
+}
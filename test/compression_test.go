@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestWithCompression(t *testing.T) {
+	clientOptions := options.Client()
+
+	err := yamgo.WithCompression("zstd", "snappy")(clientOptions)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"zstd", "snappy"}, clientOptions.Compressors)
+
+	err = yamgo.WithCompression("gzip")(options.Client())
+
+	assert.ErrorIs(t, err, yamgo.ErrInvalidCompressor)
+}
+
+func BenchmarkFindCompression(b *testing.B) {
+	payload := strings.Repeat("x", 1024)
+	docs := make([]interface{}, 10000)
+	for i := range docs {
+		docs[i] = bson.M{"payload": payload}
+	}
+
+	itemModel := yamgo.NewModel("items")
+	if _, err := itemModel.InsertMany(docs); err != nil {
+		b.Fatal(err)
+	}
+
+	defer DropCollection("items")
+
+	for _, compressors := range [][]string{nil, {"snappy"}, {"zlib"}, {"zstd"}} {
+		name := "none"
+		clientOptions := options.Client().ApplyURI(connectionURI)
+		if compressors != nil {
+			name = compressors[0]
+			if err := yamgo.WithCompression(compressors...)(clientOptions); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		client, err := mongo.Connect(context.TODO(), clientOptions)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		col := client.Database(yamgo.GetDB().Database.Name()).Collection("items")
+
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cur, err := col.Find(context.TODO(), bson.M{})
+				if err != nil {
+					b.Fatal(err)
+				}
+				results := []bson.M{}
+				if err = cur.All(context.TODO(), &results); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		_ = client.Disconnect(context.TODO())
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCopyTo(t *testing.T) {
+	itemModel := models.ItemModel()
+	archiveModel := yamgo.NewModel("items_archive")
+
+	item1 := models.ItemSchema{ID: primitive.NewObjectID()}
+	item2 := models.ItemSchema{ID: primitive.NewObjectID()}
+	item3 := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	_, err := itemModel.InsertMany([]interface{}{item1, item2, item3})
+	assert.Nil(t, err)
+
+	copied, err := itemModel.CopyTo(context.TODO(), bson.M{"_id": bson.M{"$in": []primitive.ObjectID{item1.ID, item2.ID}}}, &archiveModel)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), copied)
+
+	results := []models.ItemSchema{}
+	err = archiveModel.Find(bson.M{}, &results)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []models.ItemSchema{item1, item2}, results)
+
+	count, err := itemModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	DropCollection("items")
+	DropCollection("items_archive")
+}
+
+func TestMoveTo(t *testing.T) {
+	itemModel := models.ItemModel()
+	archiveModel := yamgo.NewModel("items_archive")
+
+	item1 := models.ItemSchema{ID: primitive.NewObjectID()}
+	item2 := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	_, err := itemModel.InsertMany([]interface{}{item1, item2})
+	assert.Nil(t, err)
+
+	moved, err := itemModel.MoveTo(context.TODO(), bson.M{"_id": item1.ID}, &archiveModel)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), moved)
+
+	result := models.ItemSchema{}
+	assert.Nil(t, archiveModel.FindByObjectID(item1.ID, &result))
+	assert.Error(t, itemModel.FindByObjectID(item1.ID, &result))
+	assert.Nil(t, itemModel.FindByObjectID(item2.ID, &result))
+
+	DropCollection("items")
+	DropCollection("items_archive")
+}
@@ -1,12 +1,15 @@
 package test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/nocfer/yamgo"
 	"github.com/nocfer/yamgo/test/models"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func TestCountDocuments(t *testing.T) {
@@ -48,3 +51,69 @@ func TestCountDocumentsWithFilter(t *testing.T) {
 	DropCollection("items")
 
 }
+
+func TestCountDocumentsWithExpr(t *testing.T) {
+	orderModel := yamgo.NewModel("orders")
+
+	_, err := orderModel.InsertMany([]interface{}{
+		bson.M{"shipped": 3, "ordered": 3},
+		bson.M{"shipped": 1, "ordered": 2},
+	})
+	assert.Nil(t, err)
+
+	result, err := orderModel.CountDocuments(bson.M{"$expr": bson.M{"$lt": bson.A{"$shipped", "$ordered"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result)
+
+	result, err = orderModel.CountDocuments(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result)
+
+	DropCollection("orders")
+}
+
+func TestCountDocumentsWithPipeline(t *testing.T) {
+	orderModel := yamgo.NewModel("orders")
+
+	_, err := orderModel.InsertMany([]interface{}{
+		bson.M{"status": "open", "items": bson.A{"a", "b", "c"}},
+		bson.M{"status": "open", "items": bson.A{"d"}},
+		bson.M{"status": "closed", "items": bson.A{"e", "f"}},
+	})
+	assert.Nil(t, err)
+
+	count, err := orderModel.CountDocumentsWithPipeline(context.TODO(), mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": "open"}}},
+		{{Key: "$unwind", Value: "$items"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), count)
+
+	count, err = orderModel.CountDocumentsWithPipeline(context.TODO(), mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": "cancelled"}}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	DropCollection("orders")
+}
+
+func TestCount(t *testing.T) {
+	orderModel := insertOrders(t)
+
+	for _, filter := range []bson.M{nil, {}, {"status": "open"}, {"category": "books", "amount": bson.M{"$gt": 15}}, {"status": "unknown"}} {
+		count, err := orderModel.Count(context.TODO(), filter)
+		assert.Nil(t, err)
+
+		legacy, err := orderModel.CountDocuments(filter)
+		assert.Nil(t, err)
+
+		assert.Equal(t, int64(legacy), count, filter)
+	}
+
+	count, err := orderModel.Count(context.TODO(), bson.M{"category": "books"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+
+	DropCollection("orders")
+}
@@ -0,0 +1,35 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The encryption itself is tested in csfle_test.go, which needs the cse build tag and libmongocrypt.
+
+func TestWithCSFLE(t *testing.T) {
+	kmsProviders := map[string]map[string]interface{}{"local": {"key": make([]byte, 96)}}
+	schemaMap := bson.M{"test.patients": bson.M{"bsonType": "object"}}
+
+	clientOptions := options.Client()
+	assert.Nil(t, yamgo.WithCSFLE(kmsProviders, "encryption.__keyVault", schemaMap)(clientOptions))
+
+	autoEncryption := clientOptions.AutoEncryptionOptions
+	assert.NotNil(t, autoEncryption)
+	assert.Equal(t, kmsProviders, autoEncryption.KmsProviders)
+	assert.Equal(t, "encryption.__keyVault", autoEncryption.KeyVaultNamespace)
+	assert.Equal(t, map[string]interface{}{"test.patients": bson.M{"bsonType": "object"}}, autoEncryption.SchemaMap)
+
+	assert.EqualError(t, yamgo.WithCSFLE(nil, "encryption.__keyVault", schemaMap)(options.Client()), "at least one kms provider is required")
+	assert.EqualError(t, yamgo.WithCSFLE(kmsProviders, "", schemaMap)(options.Client()), "key vault namespace can't be empty")
+}
+
+func TestCreateDataEncryptionKeyNotConfigured(t *testing.T) {
+	_, err := yamgo.CreateDataEncryptionKey(context.TODO(), "local", nil)
+	assert.ErrorIs(t, err, yamgo.ErrCSFLENotConfigured)
+}
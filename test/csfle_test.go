@@ -0,0 +1,66 @@
+//go:build cse
+
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestCSFLE(t *testing.T) {
+	// the local kms provider is for testing only
+	localKey := make([]byte, 96)
+	_, err := rand.Read(localKey)
+	assert.Nil(t, err)
+
+	kmsProviders := map[string]map[string]interface{}{"local": {"key": localKey}}
+	keyVaultNamespace := "encryption.__keyVault"
+
+	clientEncryption, err := mongo.NewClientEncryption(connectWith(t), options.ClientEncryption().
+		SetKmsProviders(kmsProviders).
+		SetKeyVaultNamespace(keyVaultNamespace))
+	assert.Nil(t, err)
+
+	dataKeyID, err := clientEncryption.CreateDataKey(context.TODO(), "local")
+	assert.Nil(t, err)
+
+	schemaMap := bson.M{
+		"test.patients": bson.M{
+			"bsonType":        "object",
+			"encryptMetadata": bson.M{"keyId": bson.A{dataKeyID}},
+			"properties": bson.M{
+				"ssn": bson.M{"encrypt": bson.M{
+					"bsonType":  "string",
+					"algorithm": "AEAD_AES_256_CBC_HMAC_SHA_512-Random",
+				}},
+			},
+		},
+	}
+
+	encryptedClient := connectWith(t, yamgo.WithCSFLE(kmsProviders, keyVaultNamespace, schemaMap))
+
+	_, err = encryptedClient.Database("test").Collection("patients").InsertOne(context.TODO(), bson.M{"name": "Jane", "ssn": "123-45-6789"})
+	assert.Nil(t, err)
+
+	var decrypted bson.M
+	err = encryptedClient.Database("test").Collection("patients").FindOne(context.TODO(), bson.M{"name": "Jane"}).Decode(&decrypted)
+	assert.Nil(t, err)
+	assert.Equal(t, "123-45-6789", decrypted["ssn"])
+
+	raw, err := yamgo.GetCollection("patients").FindOne(context.TODO(), bson.M{"name": "Jane"}).Raw()
+	assert.Nil(t, err)
+
+	subtype, _ := raw.Lookup("ssn").Binary()
+	assert.Equal(t, bsontype.BinaryEncrypted, subtype)
+
+	DropCollection("patients")
+	_ = connectWith(t).Database("encryption").Drop(context.TODO())
+}
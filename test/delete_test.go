@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestDeleteMany(t *testing.T) {
+	itemModel := models.ItemModel()
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	_, err := itemModel.InsertMany([]interface{}{item, models.ItemSchema{ID: primitive.NewObjectID()}})
+	assert.Nil(t, err)
+
+	res, err := itemModel.DeleteMany(context.TODO(), bson.M{"_id": item.ID})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), res.DeletedCount)
+
+	count, err := itemModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	DropCollection("items")
+}
+
+func TestClearCollection(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	_, err := itemModel.NativeCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName("name_1"),
+	})
+	assert.Nil(t, err)
+
+	_, err = itemModel.InsertMany([]interface{}{models.ItemSchema{ID: primitive.NewObjectID()}, models.ItemSchema{ID: primitive.NewObjectID()}})
+	assert.Nil(t, err)
+
+	err = itemModel.ClearCollection(context.TODO())
+	assert.Nil(t, err)
+
+	count, err := itemModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	indexes, err := itemModel.NativeCollection().Indexes().ListSpecifications(context.TODO())
+	assert.Nil(t, err)
+	assert.Len(t, indexes, 2)
+	assert.Equal(t, "name_1", indexes[1].Name)
+
+	DropCollection("items")
+}
+
+func TestClearIf(t *testing.T) {
+	itemModel := models.ItemModel()
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	_, err := itemModel.InsertMany([]interface{}{item, models.ItemSchema{ID: primitive.NewObjectID()}})
+	assert.Nil(t, err)
+
+	deleted, err := itemModel.ClearIf(context.TODO(), bson.M{"_id": item.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	DropCollection("items")
+}
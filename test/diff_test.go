@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiffDocuments(t *testing.T) {
+	before := bson.M{
+		"name":    "foo",
+		"age":     30,
+		"email":   "foo@bar.com",
+		"address": bson.M{"city": "Rome", "zip": "00100"},
+	}
+	after := bson.M{
+		"name":    "foo",
+		"age":     31,
+		"phone":   "123",
+		"address": bson.M{"city": "Milan", "zip": "00100"},
+	}
+
+	diff := yamgo.DiffDocuments(before, after)
+
+	assert.Equal(t, map[string]interface{}{"phone": "123"}, diff.Added)
+	assert.Equal(t, map[string]interface{}{"email": "foo@bar.com"}, diff.Removed)
+	assert.Equal(t, map[string]yamgo.FieldChange{
+		"age":          {Before: 30, After: 31},
+		"address.city": {Before: "Rome", After: "Milan"},
+	}, diff.Modified)
+	assert.Equal(t, []string{"address.zip", "name"}, diff.Unchanged)
+	assert.False(t, diff.IsEmpty())
+
+	assert.Equal(t, bson.M{
+		"$set":   bson.M{"phone": "123", "age": 31, "address.city": "Milan"},
+		"$unset": bson.M{"email": ""},
+	}, yamgo.DiffAsMongoUpdate(diff))
+}
+
+func TestDiffDocumentsDepth(t *testing.T) {
+	before := bson.M{"a": bson.M{"b": bson.M{"c": 1}}}
+	after := bson.M{"a": bson.M{"b": bson.M{"c": 2}}}
+
+	diff := yamgo.DiffDocumentsDepth(before, after, 1)
+
+	assert.Equal(t, map[string]yamgo.FieldChange{
+		"a.b": {Before: bson.M{"c": 1}, After: bson.M{"c": 2}},
+	}, diff.Modified)
+
+	assert.True(t, yamgo.DiffDocuments(before, before).IsEmpty())
+	assert.Empty(t, yamgo.DiffAsMongoUpdate(yamgo.DiffDocuments(before, before)))
+}
+
+func TestDiffCollections(t *testing.T) {
+	sourceModel := yamgo.NewModel("source_items")
+	targetModel := yamgo.NewModel("target_items")
+
+	shared := primitive.NewObjectID()
+	changed := primitive.NewObjectID()
+	onlySource := primitive.NewObjectID()
+	onlyTarget := primitive.NewObjectID()
+
+	_, err := sourceModel.InsertMany([]interface{}{
+		bson.M{"_id": shared, "name": "shared"},
+		bson.M{"_id": changed, "name": "before"},
+		bson.M{"_id": onlySource, "name": "source"},
+	})
+	assert.Nil(t, err)
+
+	_, err = targetModel.InsertMany([]interface{}{
+		bson.M{"_id": shared, "name": "shared"},
+		bson.M{"_id": changed, "name": "after"},
+		bson.M{"_id": onlyTarget, "name": "target"},
+	})
+	assert.Nil(t, err)
+
+	diff, err := yamgo.DiffCollections(context.TODO(), &sourceModel, &targetModel, "_id")
+	assert.Nil(t, err)
+	assert.Equal(t, []primitive.ObjectID{onlySource}, diff.OnlyInSource)
+	assert.Equal(t, []primitive.ObjectID{onlyTarget}, diff.OnlyInTarget)
+	assert.Equal(t, []primitive.ObjectID{changed}, diff.Different)
+	assert.False(t, diff.IsEmpty())
+
+	DropCollection("source_items")
+	DropCollection("target_items")
+}
+
+func TestDiffCollectionsOnKeyField(t *testing.T) {
+	sourceModel := yamgo.NewModel("source_items")
+	targetModel := yamgo.NewModel("target_items")
+
+	ref := primitive.NewObjectID()
+
+	_, err := sourceModel.InsertOne(bson.M{"ref": ref, "name": "same"})
+	assert.Nil(t, err)
+	_, err = targetModel.InsertOne(bson.M{"ref": ref, "name": "same"})
+	assert.Nil(t, err)
+
+	diff, err := yamgo.DiffCollections(context.TODO(), &sourceModel, &targetModel, "ref")
+	assert.Nil(t, err)
+	assert.True(t, diff.IsEmpty())
+
+	_, err = yamgo.DiffCollections(context.TODO(), &sourceModel, &targetModel, "name")
+	assert.NotNil(t, err)
+
+	DropCollection("source_items")
+	DropCollection("target_items")
+}
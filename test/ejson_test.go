@@ -0,0 +1,69 @@
+package test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEJSONRoundTrip(t *testing.T) {
+	doc := bson.M{"_id": primitive.NewObjectID(), "count": int32(3)}
+
+	data, err := yamgo.ToEJSON(doc)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"$oid"`)
+	assert.Contains(t, string(data), `{"$numberInt":"3"}`)
+
+	result := bson.M{}
+	assert.Nil(t, yamgo.FromEJSON(data, &result))
+	assert.Equal(t, doc, result)
+
+	relaxed, err := yamgo.ToRelaxedEJSON(doc)
+	assert.Nil(t, err)
+	assert.Contains(t, string(relaxed), `"count":3`)
+
+	result = bson.M{}
+	assert.Nil(t, yamgo.FromEJSON(relaxed, &result))
+	assert.Equal(t, doc["_id"], result["_id"])
+}
+
+func TestEJSONMiddleware(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	handler := yamgo.EJSONMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"_id":{"$oid":"%s"},"count":3}`, id.Hex())
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.Nil(t, err)
+	req.Header.Set("Accept", yamgo.EJSONContentType)
+
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, yamgo.EJSONContentType, res.Header.Get("Content-Type"))
+	assert.Equal(t, fmt.Sprintf(`{"_id":{"$oid":"%s"},"count":{"$numberInt":"3"}}`, id.Hex()), string(body))
+
+	res, err = http.Get(server.URL)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+}
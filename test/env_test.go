@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	u, err := url.Parse(connectionURI)
+	assert.Nil(t, err)
+
+	t.Setenv("APP_HOST", u.Hostname())
+	t.Setenv("APP_PORT", u.Port())
+	t.Setenv("APP_DATABASE", "test")
+	t.Setenv("APP_COLLECTION", "items")
+
+	model, err := yamgo.NewFromEnv("APP")
+	assert.Nil(t, err)
+	assert.Equal(t, "items", model.CollectionName())
+	assert.Equal(t, "test", model.DatabaseName())
+	assert.Same(t, yamgo.GetDB().Database.Client(), model.NativeClient())
+	assert.Nil(t, model.NativeClient().Ping(context.TODO(), nil))
+}
+
+func TestNewFromEnvMismatch(t *testing.T) {
+	u, err := url.Parse(connectionURI)
+	assert.Nil(t, err)
+
+	t.Setenv("APP_HOST", u.Hostname())
+	t.Setenv("APP_PORT", "1")
+	t.Setenv("APP_DATABASE", "test")
+	t.Setenv("APP_COLLECTION", "items")
+
+	_, err = yamgo.NewFromEnv("APP")
+	assert.ErrorContains(t, err, "can't connect to ["+u.Hostname()+":1]")
+
+	t.Setenv("APP_PORT", u.Port())
+	t.Setenv("APP_DATABASE", "other")
+
+	_, err = yamgo.NewFromEnv("APP")
+	assert.EqualError(t, err, `already connected to database "test", can't connect to "other"`)
+
+	t.Setenv("APP_DATABASE", "test")
+	t.Setenv("APP_USERNAME", "app")
+	t.Setenv("APP_PASSWORD", "secret")
+
+	_, err = yamgo.NewFromEnv("APP")
+	assert.EqualError(t, err, `already connected with other credentials, can't connect as "app"`)
+
+	t.Setenv("APP_USERNAME", "")
+	t.Setenv("APP_MAX_POOL_SIZE", "10")
+
+	_, err = yamgo.NewFromEnv("APP")
+	assert.EqualError(t, err, "already connected with another max pool size, can't use 10")
+
+	t.Setenv("APP_MAX_POOL_SIZE", "")
+	t.Setenv("APP_CONNECT_TIMEOUT_MS", "2000")
+
+	_, err = yamgo.NewFromEnv("APP")
+	assert.EqualError(t, err, "already connected with another connect timeout, can't use 2s")
+}
+
+func TestNewFromEnvValidation(t *testing.T) {
+	_, err := yamgo.NewFromEnv("MISSING")
+	assert.EqualError(t, err, "MISSING_URI or MISSING_HOST must be set")
+
+	t.Setenv("APP_URI", connectionURI)
+	t.Setenv("APP_DATABASE", "test")
+
+	_, err = yamgo.NewFromEnv("APP")
+	assert.EqualError(t, err, "APP_COLLECTION must be set")
+
+	t.Setenv("APP_COLLECTION", "items")
+	t.Setenv("APP_MAX_POOL_SIZE", "many")
+
+	_, err = yamgo.NewFromEnv("APP")
+	assert.ErrorContains(t, err, "invalid APP_MAX_POOL_SIZE")
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestWrapError(t *testing.T) {
+	userModel := models.UserModel()
+
+	assert.Nil(t, userModel.WrapError("FindOne", nil))
+
+	wrapped := userModel.WrapError("FindOne", mongo.ErrNoDocuments)
+	assert.EqualError(t, wrapped, "yamgo [users.FindOne]: mongo: no documents in result")
+	assert.ErrorIs(t, wrapped, mongo.ErrNoDocuments)
+
+	var yamgoErr *yamgo.YamgoError
+	assert.True(t, errors.As(wrapped, &yamgoErr))
+	assert.Equal(t, "users", yamgoErr.Collection)
+	assert.Equal(t, "FindOne", yamgoErr.Operation)
+	assert.Equal(t, mongo.ErrNoDocuments, yamgoErr.Cause)
+}
+
+func TestOperationErrorsAreWrapped(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	var result models.ItemSchema
+	err := itemModel.FindOne(bson.M{"_id": primitive.NewObjectID()}, &result)
+	assert.EqualError(t, err, "yamgo [items.FindOne]: mongo: no documents in result")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+	_, err = itemModel.InsertOne(item)
+	assert.Nil(t, err)
+
+	_, err = itemModel.InsertOne(item)
+	assert.True(t, mongo.IsDuplicateKeyError(err))
+	assert.Contains(t, err.Error(), "yamgo [items.InsertOne]: ")
+
+	DropCollection("items")
+}
+
+func TestErrorEnrichment(t *testing.T) {
+	userModel := yamgo.NewModel("users", yamgo.WithErrorEnrichment())
+
+	var result bson.M
+	err := userModel.FindOne(bson.M{"email": "jane@example.com", "status": "active"}, &result)
+	assert.EqualError(t, err, "yamgo [users.FindOne] (filter: {email: ..., status: ...}): mongo: no documents in result")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	assert.NotContains(t, err.Error(), "jane")
+
+	var yamgoErr *yamgo.YamgoError
+	assert.True(t, errors.As(err, &yamgoErr))
+	assert.Equal(t, bson.M{"email": "...", "status": "..."}, yamgoErr.Filter)
+
+	sanitizedModel := yamgo.NewModel("users", yamgo.WithErrorEnrichment(), yamgo.WithFilterSanitizer(yamgo.RedactFields("email")))
+	err = sanitizedModel.FindOne(bson.M{"email": "jane@example.com", "status": "active"}, &result)
+	assert.EqualError(t, err, "yamgo [users.FindOne] (filter: {email: ***, status: active}): mongo: no documents in result")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	// without the option the filter stays out of the message
+	plainModel := yamgo.NewModel("users")
+	err = plainModel.FindOne(bson.M{"email": "jane@example.com"}, &result)
+	assert.EqualError(t, err, "yamgo [users.FindOne]: mongo: no documents in result")
+}
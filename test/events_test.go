@@ -0,0 +1,69 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type deposited struct {
+	Amount int `bson:"amount"`
+}
+
+type account struct {
+	Balance int `bson:"balance"`
+}
+
+func TestEventStore(t *testing.T) {
+	store, err := yamgo.NewEventStore("events")
+	assert.Nil(t, err)
+
+	for i := 1; i <= 5; i++ {
+		seq, err := store.AppendEvent(context.TODO(), "account-1", deposited{Amount: i * 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(i), seq)
+	}
+
+	seq, err := store.AppendEvent(context.TODO(), "account-2", deposited{Amount: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), seq)
+
+	seqs := []int64{}
+	state := account{}
+	err = store.ReplayEvents(context.TODO(), "account-1", 1, func(seq int64, eventRaw bson.Raw) error {
+		var event deposited
+		if err := bson.Unmarshal(eventRaw, &event); err != nil {
+			return err
+		}
+		seqs = append(seqs, seq)
+		state.Balance += event.Amount
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, seqs)
+	assert.Equal(t, 150, state.Balance)
+
+	err = store.SnapshotState(context.TODO(), "account-1", state, 5)
+	assert.Nil(t, err)
+
+	var snapshot account
+	atSeq, err := store.LoadSnapshot(context.TODO(), "account-1", &snapshot)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), atSeq)
+	assert.Equal(t, state, snapshot)
+
+	seqs = []int64{}
+	err = store.ReplayEvents(context.TODO(), "account-1", 4, func(seq int64, eventRaw bson.Raw) error {
+		seqs = append(seqs, seq)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{4, 5}, seqs)
+
+	DropCollection("events")
+	DropCollection("events_sequences")
+	DropCollection("events_snapshots")
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExists(t *testing.T) {
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+	itemModel := models.ItemModel()
+
+	_, err := itemModel.InsertOne(&item)
+	assert.Nil(t, err)
+
+	exists, err := itemModel.Exists(context.TODO(), bson.M{"_id": item.ID})
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	exists, err = itemModel.Exists(context.TODO(), bson.M{"_id": primitive.NewObjectID()})
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	DropCollection("items")
+}
+
+func TestExistsAll(t *testing.T) {
+	item1 := models.ItemSchema{ID: primitive.NewObjectID()}
+	item2 := models.ItemSchema{ID: primitive.NewObjectID()}
+	itemModel := yamgo.NewModel("items", yamgo.WithMaxConcurrency(2))
+
+	_, err := itemModel.InsertMany([]interface{}{item1, item2})
+	assert.Nil(t, err)
+
+	filters := []bson.M{
+		{"_id": item1.ID},
+		{"_id": primitive.NewObjectID()},
+		{"_id": item2.ID},
+		{"_id": primitive.NewObjectID()},
+	}
+
+	results, err := itemModel.ExistsAll(context.TODO(), filters)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false, true, false}, results)
+
+	DropCollection("items")
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type scoreSchema struct {
+	Player    string `bson:"player"`
+	Score     int    `bson:"score"`
+	Threshold int    `bson:"threshold"`
+}
+
+func TestExprBuild(t *testing.T) {
+	assert.Equal(t, bson.M{"$gt": bson.A{"$score", "$threshold"}}, yamgo.Expr().Gt("$score", "$threshold").Build())
+	assert.Equal(t, bson.M{"$and": bson.A{
+		bson.M{"$gte": bson.A{"$score", "$threshold"}},
+		bson.M{"$lt": bson.A{"$score", 100}},
+	}}, yamgo.Expr().Gte("$score", "$threshold").Lt("$score", 100).Build())
+}
+
+func TestFindWithExpr(t *testing.T) {
+	scoreModel := yamgo.NewModel("scores")
+
+	_, err := scoreModel.InsertMany([]interface{}{
+		scoreSchema{Player: "a", Score: 10, Threshold: 20},
+		scoreSchema{Player: "b", Score: 30, Threshold: 20},
+		scoreSchema{Player: "c", Score: 50, Threshold: 40},
+	})
+	assert.Nil(t, err)
+
+	var results []scoreSchema
+	err = scoreModel.FindWithExpr(context.TODO(), yamgo.Expr().Gt("$score", "$threshold").Build(), &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Greater(t, result.Score, result.Threshold)
+	}
+
+	var result scoreSchema
+	err = scoreModel.FindOneWithExpr(context.TODO(), bson.M{"$lt": bson.A{"$score", "$threshold"}}, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, "a", result.Player)
+
+	err = scoreModel.FindOneWithExpr(context.TODO(), yamgo.Expr().Gt("$score", 100).Build(), &result)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	DropCollection("scores")
+}
+
+func TestExprConditionals(t *testing.T) {
+	scoreModel := yamgo.NewModel("scores")
+
+	_, err := scoreModel.InsertMany([]interface{}{
+		bson.M{"player": "a", "score": 5, "team": "red"},
+		bson.M{"player": "b", "score": 50, "team": nil},
+		bson.M{"player": "c", "score": 500},
+	})
+	assert.Nil(t, err)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$project", Value: bson.M{
+			"_id":    0,
+			"player": 1,
+			"team":   yamgo.Expr().IfNull("$team", "unassigned"),
+			"level":  yamgo.Expr().Cond(yamgo.Expr().Gte("$score", 50).Build(), "pro", "rookie"),
+			"rank": yamgo.Expr().Switch([]yamgo.ExprBranch{
+				{Case: yamgo.Expr().Gte("$score", 500).Build(), Then: "gold"},
+				{Case: yamgo.Expr().Gte("$score", 50).Build(), Then: "silver"},
+			}, "bronze"),
+		}}},
+		{{Key: "$sort", Value: bson.M{"player": 1}}},
+	}
+
+	var results []bson.M
+	err = scoreModel.Aggregate(pipeline, &results)
+	assert.Nil(t, err)
+	assert.Equal(t, []bson.M{
+		{"player": "a", "team": "red", "level": "rookie", "rank": "bronze"},
+		{"player": "b", "team": "unassigned", "level": "pro", "rank": "silver"},
+		{"player": "c", "team": "unassigned", "level": "pro", "rank": "gold"},
+	}, results)
+
+	DropCollection("scores")
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+)
+
+func TestFileStore(t *testing.T) {
+	store, err := yamgo.NewFileStore(context.TODO(), "documents")
+	assert.Nil(t, err)
+	files := yamgo.GetCollection("documents.files")
+
+	first, err := store.StoreFile(context.TODO(), strings.NewReader("hello world"), "a.txt", bson.M{"owner": "jane"})
+	assert.Nil(t, err)
+	assert.Equal(t, "a.txt", first.Filename)
+	assert.Equal(t, int64(11), first.SizeBytes)
+	assert.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", first.ContentHash)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	second, err := store.StoreFile(context.TODO(), strings.NewReader("hello world"), "b.txt", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	// the existing file keeps the filename of the first upload
+	assert.Equal(t, "a.txt", second.Filename)
+
+	count, err := files.CountDocuments(context.TODO(), bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	var buf bytes.Buffer
+	err = store.LoadFile(context.TODO(), second, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world", buf.String())
+
+	// the file is still referenced by second
+	err = store.DeleteFile(context.TODO(), first)
+	assert.Nil(t, err)
+
+	count, err = files.CountDocuments(context.TODO(), bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = store.DeleteFile(context.TODO(), second)
+	assert.Nil(t, err)
+
+	count, err = files.CountDocuments(context.TODO(), bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	err = store.DeleteFile(context.TODO(), second)
+	assert.ErrorIs(t, err, gridfs.ErrFileNotFound)
+
+	DropCollection("documents.files")
+	DropCollection("documents.chunks")
+}
+
+func TestFileStoreConcurrentStores(t *testing.T) {
+	store, err := yamgo.NewFileStore(context.TODO(), "reports")
+	assert.Nil(t, err)
+
+	const stores = 10
+	refs := make([]yamgo.FileRef, stores)
+
+	var wg sync.WaitGroup
+	for i := 0; i < stores; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ref, err := store.StoreFile(context.TODO(), strings.NewReader("same content"), "report.txt", nil)
+			assert.Nil(t, err)
+			refs[i] = ref
+		}(i)
+	}
+	wg.Wait()
+
+	for _, ref := range refs {
+		assert.Equal(t, refs[0].ID, ref.ID)
+	}
+
+	var file struct {
+		Metadata struct {
+			RefCount int `bson:"refCount"`
+		} `bson:"metadata"`
+	}
+	err = yamgo.GetCollection("reports.files").FindOne(context.TODO(), bson.M{}).Decode(&file)
+	assert.Nil(t, err)
+	assert.Equal(t, stores, file.Metadata.RefCount)
+
+	chunks, err := yamgo.GetCollection("reports.chunks").CountDocuments(context.TODO(), bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), chunks)
+
+	DropCollection("reports.files")
+	DropCollection("reports.chunks")
+}
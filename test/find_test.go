@@ -1,7 +1,12 @@
 package test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/nocfer/yamgo"
 	"github.com/nocfer/yamgo/test/models"
@@ -276,3 +281,724 @@ func TestAggregate(t *testing.T) {
 	DropCollection("items")
 
 }
+
+func TestFindInBatches(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	items := []interface{}{}
+	for i := 0; i < 5; i++ {
+		items = append(items, models.ItemSchema{ID: primitive.NewObjectID()})
+	}
+
+	_, err := itemModel.InsertMany(items)
+	assert.Nil(t, err)
+
+	sizes := []int{}
+	err = itemModel.FindInBatches(context.TODO(), bson.M{}, 2, func(batch []bson.Raw, batchNum int) error {
+		assert.Equal(t, len(sizes), batchNum)
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+
+	ids := []primitive.ObjectID{}
+	err = yamgo.FindInBatchesT(context.TODO(), &itemModel, bson.M{}, 2, func(batch []models.ItemSchema, batchNum int) error {
+		for _, item := range batch {
+			ids = append(ids, item.ID)
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, ids, 5)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = itemModel.FindInBatches(context.TODO(), bson.M{}, 2, func(batch []bson.Raw, batchNum int) error {
+		calls++
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+
+	DropCollection("items")
+}
+
+func TestExplainPaginatedFind(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	_, err := itemModel.InsertOne(models.ItemSchema{ID: primitive.NewObjectID()})
+	assert.Nil(t, err)
+
+	explain, err := itemModel.ExplainPaginatedFind(context.TODO(), yamgo.PaginationFindParams{
+		Query:         bson.M{},
+		Limit:         10,
+		SortAscending: true,
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, explain, "queryPlanner")
+	assert.Contains(t, explain["queryPlanner"], "winningPlan")
+
+	// with an expansion the explained aggregation runs the $lookup stages too
+	explain, err = itemModel.ExplainPaginatedFind(context.TODO(), yamgo.PaginationFindParams{
+		Query:     bson.M{},
+		Limit:     10,
+		Expansion: []yamgo.PopulateOptions{{Collection: "users", LocalField: "owner"}},
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, explain, "stages")
+	assert.Contains(t, fmt.Sprint(explain["stages"]), "$lookup")
+
+	DropCollection("items")
+}
+
+func TestFindFirstAndLast(t *testing.T) {
+	scoreModel := yamgo.NewModel("scores")
+
+	_, err := scoreModel.InsertMany([]interface{}{
+		bson.M{"player": "b", "score": 20},
+		bson.M{"player": "a", "score": 10},
+		bson.M{"player": "c", "score": 30},
+	})
+	assert.Nil(t, err)
+
+	var first bson.M
+	err = scoreModel.FindFirst(context.TODO(), nil, "score", &first, yamgo.FindProjection(bson.M{"_id": 0, "player": 1}))
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"player": "a"}, first)
+
+	var last bson.M
+	err = scoreModel.FindLast(context.TODO(), bson.M{}, "score", &last)
+	assert.Nil(t, err)
+	assert.Equal(t, "c", last["player"])
+	assert.EqualValues(t, 30, last["score"])
+
+	err = scoreModel.FindLast(context.TODO(), bson.M{"score": bson.M{"$gt": 100}}, "score", &last)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	DropCollection("scores")
+}
+
+func TestFindWithSkip(t *testing.T) {
+	scoreModel := yamgo.NewModel("scores")
+
+	docs := []interface{}{}
+	for i := 10; i > 0; i-- {
+		docs = append(docs, bson.M{"score": i})
+	}
+	_, err := scoreModel.InsertMany(docs)
+	assert.Nil(t, err)
+
+	var results []bson.M
+	err = scoreModel.FindWithSkip(context.TODO(), nil, bson.D{{Key: "score", Value: 1}}, 5, 3, &results)
+	assert.Nil(t, err)
+
+	scores := []int32{}
+	for _, result := range results {
+		scores = append(scores, result["score"].(int32))
+	}
+	assert.Equal(t, []int32{6, 7, 8}, scores)
+
+	DropCollection("scores")
+}
+
+type productSchema struct {
+	Name     string `bson:"name"`
+	Category string `bson:"category"`
+}
+
+func TestFindAndGroupBy(t *testing.T) {
+	productModel := yamgo.NewModel("products")
+
+	_, err := productModel.InsertMany([]interface{}{
+		productSchema{Name: "apple", Category: "fruit"},
+		productSchema{Name: "carrot", Category: "vegetable"},
+		productSchema{Name: "pear", Category: "fruit"},
+		productSchema{Name: "bread", Category: "bakery"},
+	})
+	assert.Nil(t, err)
+
+	groups, err := yamgo.FindAndGroupBy(context.TODO(), &productModel, bson.M{"category": bson.M{"$ne": "bakery"}}, func(p productSchema) string {
+		return p.Category
+	})
+	assert.Nil(t, err)
+	assert.Len(t, groups, 2)
+	assert.ElementsMatch(t, []productSchema{{Name: "apple", Category: "fruit"}, {Name: "pear", Category: "fruit"}}, groups["fruit"])
+	assert.Equal(t, []productSchema{{Name: "carrot", Category: "vegetable"}}, groups["vegetable"])
+
+	DropCollection("products")
+}
+
+type userDTO struct {
+	ID    string
+	Email string
+}
+
+func TestFindPipe(t *testing.T) {
+	userModel := models.UserModel()
+
+	users := []interface{}{
+		models.UserSchema{ID: primitive.NewObjectID(), Email: "a@example.com"},
+		models.UserSchema{ID: primitive.NewObjectID(), Email: "b@example.com"},
+	}
+	_, err := userModel.InsertMany(users)
+	assert.Nil(t, err)
+
+	dtos, err := yamgo.FindPipe(context.TODO(), &userModel, bson.M{}, func(u models.UserSchema) (userDTO, error) {
+		return userDTO{ID: u.ID.Hex(), Email: u.Email}, nil
+	})
+	assert.Nil(t, err)
+	assert.IsType(t, []userDTO{}, dtos)
+	assert.Len(t, dtos, 2)
+	assert.ElementsMatch(t, []string{"a@example.com", "b@example.com"}, []string{dtos[0].Email, dtos[1].Email})
+
+	errTransform := errors.New("transform failed")
+	_, err = yamgo.FindPipe(context.TODO(), &userModel, nil, func(u models.UserSchema) (userDTO, error) {
+		return userDTO{}, errTransform
+	})
+	assert.ErrorIs(t, err, errTransform)
+
+	DropCollection("users")
+}
+
+func TestPaginatedFindFaceted(t *testing.T) {
+	scoreModel := yamgo.NewModel("scores")
+
+	docs := []interface{}{}
+	for i := 1; i <= 5; i++ {
+		docs = append(docs, scoreSchema{Player: string(rune('a' + i - 1)), Score: i * 10})
+	}
+	_, err := scoreModel.InsertMany(docs)
+	assert.Nil(t, err)
+
+	params := yamgo.PaginationFindParams{
+		Query:          bson.M{"score": bson.M{"$gt": 10}},
+		Limit:          3,
+		PaginatedField: "score",
+		SortAscending:  true,
+		CountTotal:     true,
+	}
+
+	results := []scoreSchema{}
+	page, err := scoreModel.PaginatedFindFaceted(context.TODO(), params, &results)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, page.Count)
+	assert.True(t, page.HasNext)
+	assert.False(t, page.HasPrevious)
+	assert.Equal(t, []string{"b", "c", "d"}, []string{results[0].Player, results[1].Player, results[2].Player})
+
+	params.Next = page.Next
+	page, err = scoreModel.PaginatedFindFaceted(context.TODO(), params, &results)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, page.Count)
+	assert.False(t, page.HasNext)
+	assert.True(t, page.HasPrevious)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "e", results[0].Player)
+
+	params = yamgo.PaginationFindParams{Query: bson.M{"score": bson.M{"$gt": 100}}, Limit: 3, CountTotal: true}
+	page, err = scoreModel.PaginatedFindFaceted(context.TODO(), params, &results)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, page.Count)
+	assert.Len(t, results, 0)
+
+	DropCollection("scores")
+}
+
+// BenchmarkPaginatedFindFaceted compares a count and a find with the single aggregation of PaginatedFindFaceted,
+// which needs MongoDB 4.4+ for $unionWith.
+func BenchmarkPaginatedFindFaceted(b *testing.B) {
+	if testing.Short() {
+		b.Skip("inserts 1M documents")
+	}
+
+	scoreModel := yamgo.NewModel("scores")
+	defer DropCollection("scores")
+
+	const total, batchSize = 1000000, 10000
+	for inserted := 0; inserted < total; inserted += batchSize {
+		docs := make([]interface{}, batchSize)
+		for i := range docs {
+			docs[i] = bson.M{"score": inserted + i, "even": (inserted+i)%2 == 0}
+		}
+		if _, err := scoreModel.InsertMany(docs); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	params := yamgo.PaginationFindParams{Query: bson.M{"even": true}, Limit: 50, CountTotal: true}
+
+	b.Run("count+find", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			results := []bson.M{}
+			if _, err := scoreModel.PaginatedFind(params, &results); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("facet", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			results := []bson.M{}
+			if _, err := scoreModel.PaginatedFindFaceted(context.TODO(), params, &results); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestFindComment(t *testing.T) {
+	db := yamgo.GetDB().Database
+	assert.Nil(t, db.RunCommand(context.TODO(), bson.D{{Key: "profile", Value: 2}}).Err())
+	defer db.RunCommand(context.TODO(), bson.D{{Key: "profile", Value: 0}})
+
+	itemModel := models.ItemModel()
+	_, err := itemModel.InsertOne(models.ItemSchema{ID: primitive.NewObjectID()})
+	assert.Nil(t, err)
+
+	results := []models.ItemSchema{}
+	err = itemModel.Find(bson.M{}, &results, yamgo.Comment("find-comment-test"))
+	assert.Nil(t, err)
+
+	var result models.ItemSchema
+	err = itemModel.FindOne(bson.M{}, &result, yamgo.WithComment("find-one-comment-test"))
+	assert.Nil(t, err)
+
+	_, err = itemModel.PaginatedFind(yamgo.PaginationFindParams{Query: bson.M{}, Limit: 1, Comment: "paginated-comment-test"}, &results)
+	assert.Nil(t, err)
+
+	for _, comment := range []string{"find-comment-test", "find-one-comment-test", "paginated-comment-test"} {
+		count, err := db.Collection("system.profile").CountDocuments(context.TODO(), bson.M{"command.comment": comment})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count, comment)
+	}
+
+	DropCollection("items")
+}
+
+func TestFindOneMap(t *testing.T) {
+	fooModel := models.FooModel()
+
+	ref := primitive.NewObjectID()
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+	_, err := fooModel.InsertOne(bson.M{"ref": ref, "createdAt": createdAt, "tags": bson.A{"a", bson.M{"owner": ref}}})
+	assert.Nil(t, err)
+
+	doc, err := fooModel.FindOneMap(context.TODO(), bson.M{"ref": ref})
+	assert.Nil(t, err)
+
+	assert.IsType(t, "", doc["_id"])
+	assert.Equal(t, ref.Hex(), doc["ref"])
+	assert.Equal(t, createdAt, doc["createdAt"].(time.Time).UTC())
+	assert.Equal(t, []interface{}{"a", map[string]interface{}{"owner": ref.Hex()}}, doc["tags"])
+
+	_, err = json.Marshal(doc)
+	assert.Nil(t, err)
+
+	DropCollection("foos")
+}
+
+func TestFindByField(t *testing.T) {
+	accountModel := yamgo.NewModel("accounts")
+
+	_, err := accountModel.InsertMany([]interface{}{
+		bson.M{"name": "a", "status": "active"},
+		bson.M{"name": "b", "status": "closed"},
+		bson.M{"name": "c", "status": "active"},
+	})
+	assert.Nil(t, err)
+
+	var byField, byFilter []bson.M
+	err = accountModel.FindByField(context.TODO(), "status", "active", &byField)
+	assert.Nil(t, err)
+	err = accountModel.Find(bson.M{"status": "active"}, &byFilter)
+	assert.Nil(t, err)
+	assert.Len(t, byField, 2)
+	assert.Equal(t, byFilter, byField)
+
+	var one bson.M
+	err = accountModel.FindOneByField(context.TODO(), "name", "b", &one)
+	assert.Nil(t, err)
+	assert.Equal(t, "closed", one["status"])
+
+	DropCollection("accounts")
+}
+
+func TestSeekMethodBuildQueries(t *testing.T) {
+	_, sort, err := yamgo.BuildQueries(yamgo.PaginationFindParams{Query: bson.M{}, Limit: 2, PaginatedField: "name", SortAscending: true, SeekMethod: yamgo.KeysetByID})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.D{{Key: "_id", Value: 1}}, sort)
+
+	_, sort, err = yamgo.BuildQueries(yamgo.PaginationFindParams{Query: bson.M{}, Limit: 2, PaginatedField: "name", SortAscending: true})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}, sort)
+}
+
+func TestSeekMethod(t *testing.T) {
+	accountModel := yamgo.NewModel("accounts")
+
+	// names sort like the _id, so both methods page in the same order
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		_, err := accountModel.InsertOne(bson.M{"_id": primitive.NewObjectID(), "name": name})
+		assert.Nil(t, err)
+	}
+
+	pages := func(params yamgo.PaginationFindParams) []string {
+		names := []string{}
+		for {
+			results := []bson.M{}
+			page, err := accountModel.PaginatedFind(params, &results)
+			assert.Nil(t, err)
+			for _, result := range results {
+				names = append(names, result["name"].(string))
+			}
+			if !page.HasNext {
+				return names
+			}
+			params.Next = page.Next
+		}
+	}
+
+	params := yamgo.PaginationFindParams{Query: bson.M{}, Limit: 2, PaginatedField: "name", SortAscending: true}
+	cursorRange := pages(params)
+
+	params.SeekMethod = yamgo.KeysetByID
+	keyset := pages(params)
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, cursorRange)
+	assert.Equal(t, cursorRange, keyset)
+
+	DropCollection("accounts")
+}
+
+type articleSchema struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Description string             `bson:"description"`
+	Score       float64            `bson:"_textScore,omitempty"`
+}
+
+func TestFindTextScoreSort(t *testing.T) {
+	articleModel := yamgo.NewModel("articles")
+	ctx := context.Background()
+
+	_, err := articleModel.CreateIndex(ctx, yamgo.IndexSpec{Keys: bson.D{{Key: "description", Value: "text"}}})
+	assert.Nil(t, err)
+
+	_, err = articleModel.InsertMany([]interface{}{
+		articleSchema{Description: "coffee and tea"},
+		articleSchema{Description: "coffee coffee coffee beans"},
+		articleSchema{Description: "coffee beans from a coffee farm"},
+	})
+	assert.Nil(t, err)
+
+	var results []articleSchema
+	err = articleModel.Find(bson.M{"$text": bson.M{"$search": "coffee"}}, &results, yamgo.TextScoreSort())
+	assert.Nil(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "coffee coffee coffee beans", results[0].Description)
+	assert.Greater(t, results[0].Score, results[1].Score)
+	assert.GreaterOrEqual(t, results[1].Score, results[2].Score)
+
+	var best articleSchema
+	err = articleModel.FindOne(bson.M{"$text": bson.M{"$search": "coffee"}}, &best, yamgo.TextScoreSort(), yamgo.FindProjection(bson.M{"description": 1}))
+	assert.Nil(t, err)
+	assert.Equal(t, results[0].ID, best.ID)
+	assert.Equal(t, results[0].Score, best.Score)
+
+	DropCollection("articles")
+}
+
+func TestPaginatedFindTextScoreSort(t *testing.T) {
+	articleModel := yamgo.NewModel("articles")
+	ctx := context.Background()
+
+	_, err := articleModel.CreateIndex(ctx, yamgo.IndexSpec{Keys: bson.D{{Key: "description", Value: "text"}}})
+	assert.Nil(t, err)
+
+	_, err = articleModel.InsertMany([]interface{}{
+		articleSchema{Description: "coffee and tea"},
+		articleSchema{Description: "coffee coffee coffee beans"},
+		articleSchema{Description: "coffee beans from a coffee farm"},
+		articleSchema{Description: "green tea"},
+	})
+	assert.Nil(t, err)
+
+	params := yamgo.PaginationFindParams{
+		Query:         bson.M{"$text": bson.M{"$search": "coffee"}},
+		Limit:         1,
+		TextScoreSort: true,
+	}
+
+	var scores []float64
+	var descriptions []string
+	for {
+		var results []articleSchema
+		page, err := articleModel.PaginatedFind(params, &results)
+		assert.Nil(t, err)
+		assert.Len(t, results, 1)
+
+		scores = append(scores, results[0].Score)
+		descriptions = append(descriptions, results[0].Description)
+
+		if !page.HasNext {
+			break
+		}
+		params.Next = page.Next
+	}
+
+	assert.Len(t, descriptions, 3)
+	assert.Equal(t, "coffee coffee coffee beans", descriptions[0])
+	assert.Greater(t, scores[0], scores[1])
+	assert.GreaterOrEqual(t, scores[1], scores[2])
+
+	DropCollection("articles")
+}
+
+type rankedSchema struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty"`
+	Rank float64            `bson:"rank"`
+}
+
+func TestPaginatedFindStablePages(t *testing.T) {
+	rankedModel := yamgo.NewModel("ranked")
+	ctx := context.Background()
+
+	docs := []interface{}{}
+	for i := 1; i <= 4; i++ {
+		docs = append(docs, rankedSchema{Rank: float64(i)})
+	}
+	_, err := rankedModel.InsertMany(docs)
+	assert.Nil(t, err)
+
+	params := yamgo.PaginationFindParams{Limit: 2, PaginatedField: "rank", SortAscending: true, UseStablePages: true}
+
+	var first []rankedSchema
+	page, err := rankedModel.PaginatedFind(params, &first)
+	assert.Nil(t, err)
+	assert.Len(t, first, 2)
+
+	// a new document and a document of the first page moving after the cursor
+	_, err = rankedModel.InsertOne(&rankedSchema{Rank: 2.5})
+	assert.Nil(t, err)
+	_, err = rankedModel.UpdateOne(ctx, bson.M{"_id": first[0].ID}, bson.M{"$set": bson.M{"rank": 2.2}})
+	assert.Nil(t, err)
+
+	var second []rankedSchema
+	params.Next = page.Next
+	_, err = rankedModel.PaginatedFind(params, &second)
+	assert.Nil(t, err)
+	assert.Len(t, second, 2)
+	assert.Equal(t, 2.5, second[0].Rank)
+	assert.Equal(t, 3.0, second[1].Rank)
+
+	for _, doc := range second {
+		for _, seen := range first {
+			assert.NotEqual(t, seen.ID, doc.ID)
+		}
+	}
+
+	// without stable pages the moved document is returned again
+	params.UseStablePages = false
+	second = nil
+	_, err = rankedModel.PaginatedFind(params, &second)
+	assert.Nil(t, err)
+	assert.Equal(t, first[0].ID, second[0].ID)
+
+	DropCollection("ranked")
+}
+
+func TestPaginationParamsBuilder(t *testing.T) {
+	collation := &options.Collation{Locale: "en", Strength: 2}
+
+	params, err := yamgo.NewPaginationParams().
+		Query(bson.M{"status": "active"}).
+		Limit(20).
+		Next("next-cursor").
+		PaginatedField("name").
+		Descending(false).
+		Collation(collation).
+		Hint("name_1").
+		Projection("name,-id").
+		CountTotal(true).
+		Build()
+	assert.Nil(t, err)
+
+	assert.Equal(t, yamgo.PaginationFindParams{
+		Query:          bson.M{"status": "active"},
+		Limit:          20,
+		Next:           "next-cursor",
+		PaginatedField: "name",
+		SortAscending:  true,
+		Collation:      collation,
+		Hint:           "name_1",
+		Projection:     "name,-id",
+		CountTotal:     true,
+	}, params)
+
+	params, err = yamgo.NewPaginationParams().Limit(5).Previous("previous-cursor").Descending(true).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, yamgo.PaginationFindParams{Limit: 5, Previous: "previous-cursor"}, params)
+
+	_, err = yamgo.NewPaginationParams().Build()
+	assert.NotNil(t, err)
+
+	_, err = yamgo.NewPaginationParams().Limit(5).Next("a").Previous("b").Build()
+	assert.NotNil(t, err)
+
+	_, err = yamgo.NewPaginationParams().Limit(5).Projection("name,-password").Build()
+	assert.ErrorIs(t, err, yamgo.ErrInvalidProjection)
+}
+
+func TestFindAndPopulateOptions(t *testing.T) {
+	db := yamgo.GetDB().Database
+	assert.Nil(t, db.RunCommand(context.TODO(), bson.D{{Key: "profile", Value: 2}}).Err())
+	defer db.RunCommand(context.TODO(), bson.D{{Key: "profile", Value: 0}})
+
+	itemModel := models.ItemModel()
+	_, err := itemModel.InsertOne(models.ItemSchema{ID: primitive.NewObjectID()})
+	assert.Nil(t, err)
+
+	findOptions := options.Find().
+		SetComment("populate-options-test").
+		SetMaxTime(3 * time.Second).
+		SetCollation(&options.Collation{Locale: "en", Strength: 2})
+
+	results := []models.ItemSchema{}
+	err = itemModel.FindAndPopulate(bson.M{}, *findOptions, nil, &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+
+	var entry bson.M
+	err = db.Collection("system.profile").FindOne(context.TODO(), bson.M{"command.comment": "populate-options-test"}).Decode(&entry)
+	assert.Nil(t, err)
+
+	command := entry["command"].(bson.M)
+	assert.Equal(t, "items", command["aggregate"])
+	assert.EqualValues(t, 3000, command["maxTimeMS"])
+	assert.Equal(t, "en", command["collation"].(bson.M)["locale"])
+
+	DropCollection("items")
+}
+
+func TestFindAndPopulateDefaultLimit(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	docs := []interface{}{}
+	for i := 0; i < 3; i++ {
+		docs = append(docs, models.ItemSchema{ID: primitive.NewObjectID()})
+	}
+	_, err := itemModel.InsertMany(docs)
+	assert.Nil(t, err)
+
+	results := []models.ItemSchema{}
+	assert.NotPanics(t, func() {
+		err = itemModel.FindAndPopulate(bson.M{}, options.FindOptions{}, nil, &results)
+	})
+	assert.Nil(t, err)
+	assert.Len(t, results, 3)
+
+	var result models.ItemSchema
+	err = itemModel.FindOneAndPopulate(bson.M{"_id": docs[1].(models.ItemSchema).ID}, options.FindOptions{}, nil, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, docs[1].(models.ItemSchema).ID, result.ID)
+
+	err = itemModel.FindOneAndPopulate(bson.M{"_id": primitive.NewObjectID()}, options.FindOptions{}, nil, &result)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	DropCollection("items")
+}
+
+func TestFindAndPopulateAs(t *testing.T) {
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+	foo := models.FooSchema{ID: primitive.NewObjectID(), Item: item.ID}
+
+	itemModel := models.ItemModel()
+	fooModel := models.FooModel()
+
+	_, err := itemModel.InsertOne(&item)
+	assert.Nil(t, err)
+	_, err = fooModel.InsertOne(&foo)
+	assert.Nil(t, err)
+
+	results := []bson.M{}
+	populateOptions := []yamgo.PopulateOptions{{Collection: "items", LocalField: "item", As: "itemDoc"}}
+
+	err = fooModel.FindAndPopulate(bson.M{"_id": foo.ID}, options.FindOptions{}, populateOptions, &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+
+	assert.Equal(t, item.ID, results[0]["item"])
+	assert.Equal(t, item.ID, results[0]["itemDoc"].(bson.M)["_id"])
+
+	stages := yamgo.BuildLookupStage(populateOptions[0])
+	assert.Len(t, stages, 2)
+	assert.Len(t, yamgo.BuildLookupStage(yamgo.PopulateOptions{Collection: "items", LocalField: "item"}), 3)
+
+	DropCollection("items")
+	DropCollection("foos")
+}
+
+func TestBuildLookupStagesMerged(t *testing.T) {
+	populate := []yamgo.PopulateOptions{
+		{Collection: "items", LocalField: "item"},
+		{Collection: "users", LocalField: "ownerId", As: "owner"},
+		{Collection: "tags", LocalField: "tags"},
+	}
+
+	separate := yamgo.BuildLookupStages(populate, false)
+	merged := yamgo.BuildLookupStages(populate, true)
+	assert.Len(t, separate, 8)
+	assert.Len(t, merged, 5)
+
+	assert.Equal(t, "$addFields", merged[3][0].Key)
+	assert.Len(t, merged[3][0].Value, 3)
+	assert.Equal(t, "$unset", merged[4][0].Key)
+
+	// populating a field of a populated document depends on the previous stages
+	nested := append(populate, yamgo.PopulateOptions{Collection: "companies", LocalField: "owner.companyId", As: "company"})
+	assert.Len(t, yamgo.BuildLookupStages(nested, true), 10)
+}
+
+func TestFindAndPopulateMergeAddFields(t *testing.T) {
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+	other := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	itemModel := models.ItemModel()
+	_, err := itemModel.InsertMany([]interface{}{item, other})
+	assert.Nil(t, err)
+
+	fooModel := models.FooModel()
+	_, err = fooModel.InsertMany([]interface{}{
+		bson.M{"item": item.ID, "related": bson.A{item.ID, other.ID}},
+		bson.M{"item": other.ID, "related": bson.A{}},
+	})
+	assert.Nil(t, err)
+
+	populate := []yamgo.PopulateOptions{
+		{Collection: "items", LocalField: "item"},
+		{Collection: "items", LocalField: "related", As: "relatedItems"},
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	merged := []bson.M{}
+	err = fooModel.FindAndPopulate(bson.M{}, *findOptions, populate, &merged)
+	assert.Nil(t, err)
+
+	separateModel := yamgo.NewModel("foos", yamgo.WithMergeAddFields(false))
+	separate := []bson.M{}
+	err = separateModel.FindAndPopulate(bson.M{}, *findOptions, populate, &separate)
+	assert.Nil(t, err)
+
+	assert.Len(t, merged, 2)
+	assert.Equal(t, separate, merged)
+	assert.Equal(t, item.ID, merged[0]["item"].(bson.M)["_id"])
+	assert.Len(t, merged[0]["relatedItems"], 2)
+
+	DropCollection("items")
+	DropCollection("foos")
+}
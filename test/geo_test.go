@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type place struct {
+	Name     string `bson:"name"`
+	Kind     string `bson:"kind"`
+	Location bson.M `bson:"location"`
+}
+
+func placesModel(t *testing.T) yamgo.Model {
+	placeModel := yamgo.NewModel("places")
+
+	_, err := placeModel.CreateIndex(context.TODO(), yamgo.IndexSpec{Keys: bson.D{{Key: "location", Value: "2dsphere"}}})
+	assert.Nil(t, err)
+
+	point := func(lat float64) bson.M {
+		return bson.M{"type": "Point", "coordinates": bson.A{2.3522, lat}}
+	}
+
+	_, err = placeModel.InsertMany([]interface{}{
+		place{Name: "far", Kind: "cafe", Location: point(48.9466)},
+		place{Name: "near", Kind: "cafe", Location: point(48.8575)},
+		place{Name: "middle", Kind: "shop", Location: point(48.8656)},
+		place{Name: "middle-cafe", Kind: "cafe", Location: point(48.8657)},
+	})
+	assert.Nil(t, err)
+
+	return placeModel
+}
+
+func placeNames(places []place) []string {
+	names := []string{}
+	for _, p := range places {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestFindNear(t *testing.T) {
+	placeModel := placesModel(t)
+
+	var places []place
+	err := placeModel.FindNear(context.TODO(), "location", 2.3522, 48.8566, 2000, 0, nil, &places)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"near", "middle", "middle-cafe"}, placeNames(places))
+
+	err = placeModel.FindNear(context.TODO(), "location", 2.3522, 48.8566, 2000, 500, bson.M{"kind": "cafe"}, &places)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"middle-cafe"}, placeNames(places))
+
+	err = placeModel.FindNear(context.TODO(), "location", 2.3522, 48.8566, 0, 0, nil, &places)
+	assert.ErrorIs(t, err, yamgo.ErrNegativeDistance)
+
+	DropCollection("places")
+}
+
+func TestFindNearPaginated(t *testing.T) {
+	placeModel := placesModel(t)
+
+	params := yamgo.PaginationFindParams{Query: bson.M{"kind": "cafe"}, Limit: 1, PaginatedField: "name", SortAscending: true, CountTotal: true}
+
+	var places []place
+	page, err := placeModel.FindNearPaginated(context.TODO(), params, "location", 2.3522, 48.8566, 2000, &places)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"middle-cafe"}, placeNames(places))
+	assert.Equal(t, 2, page.Count)
+	assert.True(t, page.HasNext)
+
+	params.Next = page.Next
+	page, err = placeModel.FindNearPaginated(context.TODO(), params, "location", 2.3522, 48.8566, 2000, &places)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"near"}, placeNames(places))
+	assert.False(t, page.HasNext)
+
+	_, err = placeModel.FindNearPaginated(context.TODO(), params, "location", 2.3522, 48.8566, -1, &places)
+	assert.ErrorIs(t, err, yamgo.ErrNegativeDistance)
+
+	DropCollection("places")
+}
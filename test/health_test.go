@@ -0,0 +1,63 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func getHealth(t *testing.T, handler http.Handler) (int, map[string]interface{}) {
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+	var body map[string]interface{}
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&body))
+	return res.StatusCode, body
+}
+
+func TestHealthHandler(t *testing.T) {
+	itemModel := yamgo.NewModel("items")
+
+	status, body := getHealth(t, yamgo.HealthHandler(&itemModel))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", body["status"])
+	assert.Contains(t, body, "latency_ms")
+
+	status, body = getHealth(t, yamgo.LivenessHandler(&itemModel))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", body["status"])
+
+	status, body = getHealth(t, yamgo.ReadinessHandler(&itemModel))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", body["status"])
+
+	DropCollection("items")
+}
+
+func TestReadinessHandlerWriteBlocked(t *testing.T) {
+	validator := bson.M{"$jsonSchema": bson.M{"required": bson.A{"email"}}}
+	err := yamgo.GetDB().Database.CreateCollection(context.TODO(), "users", options.CreateCollection().SetValidator(validator))
+	assert.Nil(t, err)
+
+	userModel := yamgo.NewModel("users")
+
+	status, body := getHealth(t, yamgo.ReadinessHandler(&userModel))
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "error", body["status"])
+	assert.Equal(t, "write check failed: write-blocked by schema", body["error"])
+
+	DropCollection("users")
+}
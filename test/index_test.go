@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/nocfer/yamgo/yamgotest"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestWaitForIndex(t *testing.T) {
+	fooModel := models.FooModel()
+
+	_, err := yamgo.GetCollection("foos").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "item", Value: 1}},
+		Options: options.Index().SetName("item_1"),
+	})
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+
+	err = fooModel.WaitForIndex(ctx, "item_1", 100*time.Millisecond)
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.TODO(), 300*time.Millisecond)
+	defer cancel()
+
+	err = fooModel.WaitForIndex(ctx, "missing_1", 100*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	DropCollection("foos")
+}
+
+func explainFind(t *testing.T, collection string, filter bson.M, projection bson.M) bson.M {
+	var explain bson.M
+	err := yamgo.GetDB().Database.RunCommand(context.TODO(), bson.D{
+		{Key: "explain", Value: bson.D{{Key: "find", Value: collection}, {Key: "filter", Value: filter}, {Key: "projection", Value: projection}}},
+		{Key: "verbosity", Value: "executionStats"},
+	}).Decode(&explain)
+	assert.Nil(t, err)
+	return explain
+}
+
+func TestCreatePartialIndex(t *testing.T) {
+	accountModel := yamgo.NewModel("accounts")
+
+	_, err := accountModel.InsertMany([]interface{}{
+		bson.M{"email": "a@example.com", "status": "active"},
+		bson.M{"email": "b@example.com", "status": "inactive"},
+	})
+	assert.Nil(t, err)
+
+	name, err := accountModel.CreateIndex(context.TODO(), yamgo.IndexSpec{
+		Keys:          bson.D{{Key: "status", Value: 1}, {Key: "email", Value: 1}},
+		Name:          "active_accounts",
+		PartialFilter: bson.M{"status": "active"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "active_accounts", name)
+
+	projection := bson.M{"_id": 0, "email": 1, "status": 1}
+
+	explain := explainFind(t, "accounts", bson.M{"status": "active"}, projection)
+	executionStats := explain["executionStats"].(bson.M)
+	assert.EqualValues(t, 1, executionStats["nReturned"])
+	assert.EqualValues(t, 0, executionStats["totalDocsExamined"], "the query is covered by the index")
+
+	explain = explainFind(t, "accounts", bson.M{"status": "inactive"}, projection)
+	executionStats = explain["executionStats"].(bson.M)
+	assert.EqualValues(t, 1, executionStats["nReturned"])
+	assert.EqualValues(t, 2, executionStats["totalDocsExamined"], "inactive accounts are not indexed")
+
+	DropCollection("accounts")
+}
+
+func TestCreatePartialIndexValidation(t *testing.T) {
+	accountModel := yamgo.NewModel("accounts")
+
+	_, err := accountModel.CreateIndex(context.TODO(), yamgo.IndexSpec{
+		Keys:          bson.D{{Key: "status", Value: 1}},
+		PartialFilter: bson.M{"$or": bson.A{bson.M{"$where": "this.active"}}},
+	})
+	assert.ErrorIs(t, err, yamgo.ErrInvalidPartialFilter)
+}
+
+func TestFindWithValidatedHint(t *testing.T) {
+	fooModel := models.FooModel()
+	captured, queries := yamgotest.WithQueryCapture(&fooModel)
+	ctx := context.Background()
+
+	_, err := fooModel.CreateIndex(ctx, yamgo.IndexSpec{Keys: bson.D{{Key: "item", Value: 1}}, Name: "item_1"})
+	assert.Nil(t, err)
+
+	_, err = fooModel.InsertMany([]interface{}{bson.M{"item": "a"}, bson.M{"item": "b"}})
+	assert.Nil(t, err)
+
+	var results []bson.M
+	err = captured.FindWithValidatedHint(ctx, bson.M{"item": "a"}, "missing_1", &results)
+	assert.ErrorIs(t, err, yamgo.ErrIndexNotFound)
+	assert.Contains(t, err.Error(), "missing_1")
+	assert.Empty(t, queries.Queries())
+
+	err = captured.FindWithValidatedHint(ctx, bson.M{"item": "a"}, "item_1", &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	queries.AssertQuery(t, "FindWithHint", bson.M{"item": "a"})
+
+	DropCollection("foos")
+}
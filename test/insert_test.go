@@ -1,11 +1,18 @@
 package test
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/nocfer/yamgo"
 	"github.com/nocfer/yamgo/test/models"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestInsertOne(t *testing.T) {
@@ -37,3 +44,100 @@ func TestInsertMany(t *testing.T) {
 	DropCollection("items")
 
 }
+
+func TestInsertOneIfAbsent(t *testing.T) {
+	userModel := models.UserModel()
+
+	_, err := userModel.NativeCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	assert.Nil(t, err)
+
+	id, inserted, err := userModel.InsertOneIfAbsent(context.TODO(), models.UserSchema{Email: "jane@example.com"}, "email")
+	assert.Nil(t, err)
+	assert.True(t, inserted)
+	assert.False(t, id.IsZero())
+
+	existingID, inserted, err := userModel.InsertOneIfAbsent(context.TODO(), models.UserSchema{Email: "jane@example.com"}, "email")
+	assert.Nil(t, err)
+	assert.False(t, inserted)
+	assert.Equal(t, id, existingID)
+
+	count, err := userModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	DropCollection("users")
+}
+
+func TestFindOneOrInsertConcurrent(t *testing.T) {
+	userModel := models.UserModel()
+
+	_, err := userModel.NativeCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	assert.Nil(t, err)
+
+	var created int32
+	ids := make([]primitive.ObjectID, 20)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var user models.UserSchema
+			inserted, err := userModel.FindOneOrInsert(context.TODO(), bson.M{"email": "jane@example.com"}, models.UserSchema{Email: "jane@example.com"}, &user)
+			assert.Nil(t, err)
+			if inserted {
+				atomic.AddInt32(&created, 1)
+			}
+			ids[i] = user.ID
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), created)
+	for _, id := range ids {
+		assert.Equal(t, ids[0], id)
+	}
+
+	count, err := userModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	DropCollection("users")
+}
+
+func TestInsertIfNotExistsMany(t *testing.T) {
+	permissionModel := yamgo.NewModel("permissions")
+	ctx := context.Background()
+
+	seed := func(names ...string) []interface{} {
+		docs := make([]interface{}, len(names))
+		for i, name := range names {
+			docs[i] = bson.M{"name": name}
+		}
+		return docs
+	}
+
+	inserted, err := permissionModel.InsertIfNotExistsMany(ctx, seed("read", "write", "delete", "admin", "audit"), "name")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), inserted)
+
+	inserted, err = permissionModel.InsertIfNotExistsMany(ctx, seed("read", "write", "delete", "export", "import"), "name")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), inserted)
+
+	count, err := permissionModel.CountDocuments(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, count)
+
+	_, err = permissionModel.InsertIfNotExistsMany(ctx, []interface{}{bson.M{"label": "x"}}, "name")
+	assert.NotNil(t, err)
+
+	DropCollection("permissions")
+}
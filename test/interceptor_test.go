@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type tenantDocument struct {
+	Name     string `bson:"name"`
+	TenantID string `bson:"tenantID"`
+}
+
+func TestQueryInterceptor(t *testing.T) {
+	tenantModel := yamgo.NewModel("tenant_documents", yamgo.WithQueryInterceptor(func(f bson.M) bson.M {
+		f["tenantID"] = "acme"
+		return f
+	}))
+
+	_, err := tenantModel.InsertMany([]interface{}{
+		tenantDocument{Name: "a", TenantID: "acme"},
+		tenantDocument{Name: "a", TenantID: "globex"},
+		tenantDocument{Name: "b", TenantID: "acme"},
+		tenantDocument{Name: "b", TenantID: "globex"},
+	})
+	assert.Nil(t, err)
+
+	filter := bson.M{"name": "a"}
+
+	var doc tenantDocument
+	err = tenantModel.FindOne(filter, &doc)
+	assert.Nil(t, err)
+	assert.Equal(t, "acme", doc.TenantID)
+	assert.Equal(t, bson.M{"name": "a"}, filter)
+
+	docs := []tenantDocument{}
+	err = tenantModel.Find(bson.M{}, &docs)
+	assert.Nil(t, err)
+	assert.Len(t, docs, 2)
+
+	docs = []tenantDocument{}
+	err = tenantModel.FindAndPopulate(bson.M{}, *options.Find(), nil, &docs)
+	assert.Nil(t, err)
+	assert.Len(t, docs, 2)
+
+	count, err := tenantModel.CountDocuments(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	err = tenantModel.FindOneAndModify(context.TODO(), bson.M{"name": "b"}, bson.M{"$set": bson.M{"name": "c"}}, &doc, yamgo.ModifyReturnNew())
+	assert.Nil(t, err)
+	assert.Equal(t, tenantDocument{Name: "c", TenantID: "acme"}, doc)
+
+	res, err := tenantModel.DeleteMany(context.TODO(), bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), res.DeletedCount)
+
+	remaining, err := tenantModel.NativeCollection().CountDocuments(context.TODO(), bson.M{"tenantID": "globex"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), remaining)
+
+	DropCollection("tenant_documents")
+}
+
+func TestResultInterceptor(t *testing.T) {
+	upperModel := yamgo.NewModel("tenant_documents", yamgo.WithResultInterceptor(func(raw bson.Raw) (bson.Raw, error) {
+		var doc bson.M
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		doc["name"] = strings.ToUpper(doc["name"].(string))
+		return bson.Marshal(doc)
+	}))
+
+	_, err := upperModel.InsertMany([]interface{}{
+		tenantDocument{Name: "a", TenantID: "acme"},
+		tenantDocument{Name: "b", TenantID: "acme"},
+	})
+	assert.Nil(t, err)
+
+	var doc tenantDocument
+	err = upperModel.FindOne(bson.M{"name": "a"}, &doc)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", doc.Name)
+
+	docs := []tenantDocument{}
+	err = upperModel.Find(bson.M{}, &docs)
+	assert.Nil(t, err)
+	assert.Equal(t, []tenantDocument{{Name: "A", TenantID: "acme"}, {Name: "B", TenantID: "acme"}}, docs)
+
+	failingModel := yamgo.NewModel("tenant_documents", yamgo.WithResultInterceptor(func(raw bson.Raw) (bson.Raw, error) {
+		return nil, errors.New("decryption failed")
+	}))
+
+	err = failingModel.Find(bson.M{}, &docs)
+	assert.EqualError(t, err, "decryption failed")
+
+	DropCollection("tenant_documents")
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLockCollection(t *testing.T) {
+	var wg sync.WaitGroup
+	locks := make([]yamgo.Lock, 2)
+	errs := make([]error, 2)
+
+	for i := range locks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			locks[i], errs[i] = yamgo.LockCollection(context.TODO(), "migration", time.Minute)
+		}(i)
+	}
+	wg.Wait()
+
+	held := 0
+	for i, err := range errs {
+		if err == nil {
+			held++
+			defer locks[i].Unlock(context.TODO())
+		} else {
+			assert.ErrorIs(t, err, yamgo.ErrLockHeld)
+		}
+	}
+	assert.Equal(t, 1, held)
+
+	DropCollection(yamgo.LocksCollection)
+}
+
+func TestUnlock(t *testing.T) {
+	lock, err := yamgo.LockCollection(context.TODO(), "migration", time.Minute)
+	assert.Nil(t, err)
+
+	assert.Nil(t, lock.Unlock(context.TODO()))
+
+	lock, err = yamgo.LockCollection(context.TODO(), "migration", time.Minute)
+	assert.Nil(t, err)
+	assert.Nil(t, lock.Unlock(context.TODO()))
+
+	DropCollection(yamgo.LocksCollection)
+}
+
+func TestLockRefresh(t *testing.T) {
+	lock, err := yamgo.LockCollection(context.TODO(), "migration", 300*time.Millisecond)
+	assert.Nil(t, err)
+
+	time.Sleep(600 * time.Millisecond)
+
+	_, err = yamgo.LockCollection(context.TODO(), "migration", time.Minute)
+	assert.ErrorIs(t, err, yamgo.ErrLockHeld)
+
+	assert.Nil(t, lock.Unlock(context.TODO()))
+
+	DropCollection(yamgo.LocksCollection)
+}
+
+func TestLockTTLTooShort(t *testing.T) {
+	_, err := yamgo.LockCollection(context.TODO(), "migration", time.Millisecond)
+	assert.NotNil(t, err)
+
+	_, err = yamgo.LockCollection(context.TODO(), "migration", 0)
+	assert.NotNil(t, err)
+}
+
+func TestLockExpired(t *testing.T) {
+	_, err := yamgo.GetCollection(yamgo.LocksCollection).InsertOne(context.TODO(), bson.M{
+		"_id":    "migration",
+		"holder": "crashed-host",
+		"expiry": time.Now().Add(-time.Second),
+	})
+	assert.Nil(t, err)
+
+	lock, err := yamgo.LockCollection(context.TODO(), "migration", time.Minute)
+	assert.Nil(t, err)
+	assert.Nil(t, lock.Unlock(context.TODO()))
+
+	DropCollection(yamgo.LocksCollection)
+}
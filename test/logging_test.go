@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	slowModel := yamgo.NewModel("items",
+		yamgo.WithSlowQueryLog(time.Nanosecond, logger),
+		yamgo.WithFilterSanitizer(yamgo.RedactFields("password")),
+	)
+
+	results := []bson.M{}
+	err := slowModel.Find(bson.M{"name": "foo", "password": "secret"}, &results)
+	assert.Nil(t, err)
+
+	entry := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "Find", entry["op"])
+	assert.Equal(t, "items", entry["collection"])
+	assert.Equal(t, map[string]interface{}{"name": "foo", "password": "***"}, entry["filter"])
+
+	buf.Reset()
+
+	fastModel := yamgo.NewModel("items", yamgo.WithSlowQueryLog(time.Hour, logger))
+	err = fastModel.Find(bson.M{}, &results)
+	assert.Nil(t, err)
+	assert.Empty(t, buf.String())
+
+	DropCollection("items")
+}
+
+func TestSlowQueryLogThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	itemModel := yamgo.NewModel("items")
+	_, err := itemModel.InsertOne(bson.M{"name": "foo"})
+	assert.Nil(t, err)
+
+	// the interceptor runs while the documents are decoded, so the Find takes at least 50ms
+	slowDecode := yamgo.WithResultInterceptor(func(raw bson.Raw) (bson.Raw, error) {
+		time.Sleep(50 * time.Millisecond)
+		return raw, nil
+	})
+
+	slowModel := yamgo.NewModel("items", slowDecode, yamgo.WithSlowQueryLog(20*time.Millisecond, logger))
+
+	results := []bson.M{}
+	err = slowModel.Find(bson.M{"name": "foo"}, &results)
+	assert.Nil(t, err)
+
+	entry := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "Find", entry["op"])
+	assert.GreaterOrEqual(t, entry["duration_ms"], float64(50))
+	assert.Equal(t, float64(20), entry["threshold_ms"])
+
+	buf.Reset()
+
+	// the same operation under a higher threshold isn't logged
+	patientModel := yamgo.NewModel("items", slowDecode, yamgo.WithSlowQueryLog(time.Minute, logger))
+	err = patientModel.Find(bson.M{"name": "foo"}, &results)
+	assert.Nil(t, err)
+	assert.Empty(t, buf.String())
+
+	DropCollection("items")
+}
+
+func TestRedactFields(t *testing.T) {
+	sanitize := yamgo.RedactFields("password", "token")
+
+	filter := bson.M{
+		"email": "foo@bar.com",
+		"token": "abc",
+		"$or":   []bson.M{{"password": "secret"}, {"nested": bson.M{"token": "def"}}},
+	}
+
+	assert.Equal(t, bson.M{
+		"email": "foo@bar.com",
+		"token": "***",
+		"$or":   []bson.M{{"password": "***"}, {"nested": bson.M{"token": "***"}}},
+	}, sanitize(filter))
+
+	assert.Equal(t, "abc", filter["token"])
+}
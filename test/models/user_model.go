@@ -0,0 +1,15 @@
+package models
+
+import (
+	"github.com/nocfer/yamgo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UserSchema struct {
+	ID    primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Email string             `json:"email,omitempty" bson:"email,omitempty" validate:"required,email"`
+}
+
+func UserModel(opts ...yamgo.Option) yamgo.Model {
+	return yamgo.NewModel("users", opts...)
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func connectWith(t *testing.T, opts ...yamgo.ClientOption) *mongo.Client {
+	clientOptions := options.Client().ApplyURI(connectionURI)
+	for _, opt := range opts {
+		assert.Nil(t, opt(clientOptions))
+	}
+
+	client, err := mongo.Connect(context.TODO(), clientOptions)
+	assert.Nil(t, err)
+
+	t.Cleanup(func() {
+		_ = client.Disconnect(context.TODO())
+	})
+
+	return client
+}
+
+func TestConnectionPoolMonitor(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := connectWith(t, yamgo.WithConnectionPoolMonitor(yamgo.DefaultPoolLogger(logger)))
+
+	cur, err := client.Database("test").Collection("items").Find(context.TODO(), bson.M{})
+	assert.Nil(t, err)
+	assert.Nil(t, cur.Close(context.TODO()))
+
+	assert.Contains(t, buf.String(), event.GetSucceeded)
+	assert.Contains(t, buf.String(), event.ConnectionCreated)
+
+	assert.Error(t, yamgo.WithConnectionPoolMonitor(nil)(options.Client()))
+}
+
+func TestCommandLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := connectWith(t, yamgo.WithCommandMonitor(yamgo.NewCommandLogger(logger, []string{"password"})))
+
+	res := client.Database("test").Collection("users").FindOne(context.TODO(), bson.M{"password": "secret"})
+	assert.ErrorIs(t, res.Err(), mongo.ErrNoDocuments)
+
+	entries := []map[string]interface{}{}
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		entry := map[string]interface{}{}
+		assert.Nil(t, decoder.Decode(&entry))
+		if entry["command_name"] == "find" {
+			entries = append(entries, entry)
+		}
+	}
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "yamgo: command started", entries[0]["msg"])
+	assert.Equal(t, "yamgo: command succeeded", entries[1]["msg"])
+	assert.Equal(t, entries[0]["request_id"], entries[1]["request_id"])
+	assert.NotContains(t, buf.String(), "secret")
+	assert.Equal(t, "***", entries[0]["command"].(map[string]interface{})["filter"].(map[string]interface{})["password"])
+}
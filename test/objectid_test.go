@@ -0,0 +1,166 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestConvertToObjectID(t *testing.T) {
+	id := yamgo.NewObjectID()
+
+	converted, err := yamgo.ConvertToObjectID(id.Hex())
+
+	assert.Nil(t, err)
+	assert.Equal(t, id, converted)
+	assert.True(t, yamgo.IsValidObjectID(id.Hex()))
+
+	_, err = yamgo.ConvertToObjectID("not-an-id")
+
+	assert.ErrorIs(t, err, yamgo.ErrInvalidID)
+	assert.False(t, yamgo.IsValidObjectID("not-an-id"))
+}
+
+func TestMustConvertToObjectID(t *testing.T) {
+	id := yamgo.NewObjectID()
+
+	assert.Equal(t, id, yamgo.MustConvertToObjectID(id.Hex()))
+	assert.Panics(t, func() {
+		yamgo.MustConvertToObjectID("not-an-id")
+	})
+}
+
+func TestObjectIDTime(t *testing.T) {
+	now := time.Now()
+
+	id := yamgo.ObjectIDFromTime(now)
+
+	assert.Equal(t, now.Unix(), yamgo.ObjectIDToTime(id).Unix())
+}
+
+func TestFindCreatedBetween(t *testing.T) {
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+	itemModel := models.ItemModel()
+
+	_, err := itemModel.InsertOne(&item)
+	assert.Nil(t, err)
+
+	assert.WithinDuration(t, time.Now(), yamgo.ObjectIDToTime(item.ID), time.Second)
+
+	results := []models.ItemSchema{}
+	err = itemModel.FindCreatedBetween(context.TODO(), time.Now().Add(-time.Minute), time.Now().Add(time.Minute), &results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, item.ID, results[0].ID)
+
+	err = itemModel.FindCreatedBetween(context.TODO(), time.Now().Add(-time.Hour), time.Now().Add(-time.Minute), &results)
+
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+
+	DropCollection("items")
+}
+
+func TestFindChangedSince(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	before := models.ItemSchema{ID: yamgo.ObjectIDFromTime(time.Now().Add(-time.Hour))}
+	after := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	_, err := itemModel.InsertMany([]interface{}{before, after})
+	assert.Nil(t, err)
+
+	checkpoint := time.Now().Add(-time.Minute)
+
+	results := []models.ItemSchema{}
+	err = itemModel.FindChangedSince(context.TODO(), checkpoint, &results)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, after.ID, results[0].ID)
+
+	cur, err := itemModel.FindChangedSinceCursor(context.TODO(), checkpoint)
+	assert.Nil(t, err)
+
+	ids := []primitive.ObjectID{}
+	for cur.Next(context.TODO()) {
+		var item models.ItemSchema
+		assert.Nil(t, cur.Decode(&item))
+		ids = append(ids, item.ID)
+	}
+	assert.Nil(t, cur.Err())
+	assert.Nil(t, cur.Close(context.TODO()))
+	assert.Equal(t, []primitive.ObjectID{after.ID}, ids)
+
+	DropCollection("items")
+}
+
+func TestFindObjectIDs(t *testing.T) {
+	var fetched []bson.Raw
+	fooModel := yamgo.NewModel("foos", yamgo.WithResultInterceptor(func(raw bson.Raw) (bson.Raw, error) {
+		fetched = append(fetched, raw)
+		return raw, nil
+	}))
+
+	foos := []interface{}{
+		models.FooSchema{ID: primitive.NewObjectID(), Item: "a"},
+		models.FooSchema{ID: primitive.NewObjectID(), Item: "b"},
+		models.FooSchema{ID: primitive.NewObjectID(), Item: "c"},
+	}
+	_, err := fooModel.InsertMany(foos)
+	assert.Nil(t, err)
+
+	ids, err := fooModel.FindObjectIDs(context.TODO(), nil)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []primitive.ObjectID{
+		foos[0].(models.FooSchema).ID,
+		foos[1].(models.FooSchema).ID,
+		foos[2].(models.FooSchema).ID,
+	}, ids)
+
+	assert.Len(t, fetched, 3)
+	for _, raw := range fetched {
+		elements, err := raw.Elements()
+		assert.Nil(t, err)
+		assert.Len(t, elements, 1)
+	}
+
+	hexIDs, err := fooModel.FindStringIDs(context.TODO(), bson.M{"item": "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{foos[1].(models.FooSchema).ID.Hex()}, hexIDs)
+
+	DropCollection("foos")
+}
+
+func TestHasChanges(t *testing.T) {
+	itemModel := models.ItemModel()
+	ctx := context.Background()
+
+	checkpoint, err := itemModel.LatestID(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, primitive.NilObjectID, checkpoint)
+
+	_, err = itemModel.InsertOne(&models.ItemSchema{})
+	assert.Nil(t, err)
+
+	changed, err := itemModel.HasChanges(ctx, checkpoint)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	checkpoint, err = itemModel.LatestID(ctx)
+	assert.Nil(t, err)
+	assert.NotEqual(t, primitive.NilObjectID, checkpoint)
+
+	changed, err = itemModel.HasChanges(ctx, checkpoint)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	DropCollection("items")
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestWithOIDCAuth(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "yamgo", r.PostForm.Get("client_id"))
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		assert.Equal(t, "db.read db.write", r.PostForm.Get("scope"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token-1","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	clientOptions := options.Client()
+	err := yamgo.WithOIDCAuth(server.URL, "yamgo", "secret", []string{"db.read", "db.write"})(clientOptions)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "MONGODB-OIDC", clientOptions.Auth.AuthMechanism)
+	assert.NotNil(t, clientOptions.Auth.OIDCMachineCallback)
+
+	credential, err := clientOptions.Auth.OIDCMachineCallback(context.TODO(), &options.OIDCArgs{Version: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", credential.AccessToken)
+	assert.NotNil(t, credential.ExpiresAt)
+
+	// the cached token is reused until it is about to expire
+	_, err = clientOptions.Auth.OIDCMachineCallback(context.TODO(), &options.OIDCArgs{Version: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestWithOIDCAuthTokenError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	clientOptions := options.Client()
+	err := yamgo.WithOIDCAuth(server.URL, "yamgo", "wrong", nil)(clientOptions)
+	assert.Nil(t, err)
+
+	_, err = clientOptions.Auth.OIDCMachineCallback(context.TODO(), &options.OIDCArgs{Version: 1})
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseProjection(t *testing.T) {
+	tests := []struct {
+		projection string
+		expected   bson.M
+		invalid    bool
+	}{
+		{projection: "", expected: nil},
+		{projection: "   ", expected: nil},
+		{projection: "name", expected: bson.M{"name": 1}},
+		{projection: "name,email", expected: bson.M{"name": 1, "email": 1}},
+		{projection: " name , email ", expected: bson.M{"name": 1, "email": 1}},
+		{projection: "id", expected: bson.M{"_id": 1}},
+		{projection: "_id,name", expected: bson.M{"_id": 1, "name": 1}},
+		{projection: "valid,paid", expected: bson.M{"valid": 1, "paid": 1}},
+		{projection: "address.city", expected: bson.M{"address.city": 1}},
+		{projection: "name,name", expected: bson.M{"name": 1}},
+		{projection: "-password", expected: bson.M{"password": 0}},
+		{projection: "-password,-token", expected: bson.M{"password": 0, "token": 0}},
+		{projection: "-id", expected: bson.M{"_id": 0}},
+		{projection: "- password", expected: bson.M{"password": 0}},
+		{projection: "name,-id", expected: bson.M{"name": 1, "_id": 0}},
+		{projection: "-_id,name,email", expected: bson.M{"name": 1, "email": 1, "_id": 0}},
+		{projection: "name,-password", invalid: true},
+		{projection: "-password,name", invalid: true},
+		{projection: "id,-id", invalid: true},
+		{projection: "name,,email", invalid: true},
+		{projection: "name,", invalid: true},
+		{projection: "-", invalid: true},
+		{projection: "--name", invalid: true},
+		{projection: "$where", invalid: true},
+	}
+
+	for _, test := range tests {
+		projection, err := yamgo.ParseProjection(test.projection)
+		if test.invalid {
+			assert.ErrorIs(t, err, yamgo.ErrInvalidProjection, test.projection)
+			assert.Nil(t, projection, test.projection)
+			continue
+		}
+		assert.Nil(t, err, test.projection)
+		assert.Equal(t, test.expected, projection, test.projection)
+	}
+}
@@ -0,0 +1,38 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter, err := yamgo.NewRateLimiter("rate_limits")
+	assert.Nil(t, err)
+
+	window := time.Second
+
+	for i := 0; i < 3; i++ {
+		res, err := limiter.RateLimiter(context.TODO(), "client", 3, window)
+		assert.Nil(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2-i, res.Remaining)
+	}
+
+	res, err := limiter.RateLimiter(context.TODO(), "client", 3, window)
+	assert.Nil(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	time.Sleep(window + 100*time.Millisecond)
+
+	res, err = limiter.RateLimiter(context.TODO(), "client", 3, window)
+	assert.Nil(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+
+	DropCollection("rate_limits")
+}
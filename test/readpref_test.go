@@ -0,0 +1,96 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
+)
+
+func TestReadPreferenceTags(t *testing.T) {
+	itemModel := yamgo.NewModel("items", yamgo.WithReadPreferenceTags(bson.D{{Key: "region", Value: "eu-west"}, {Key: "zone", Value: "a"}}))
+
+	rp := itemModel.ReadPreference()
+
+	assert.Equal(t, readpref.NearestMode, rp.Mode())
+	assert.Equal(t, []tag.Set{{{Name: "region", Value: "eu-west"}, {Name: "zone", Value: "a"}}}, rp.TagSets())
+
+	defaultModel := models.ItemModel()
+	assert.Nil(t, defaultModel.ReadPreference())
+}
+
+func TestReadPreferenceTagsPerOperation(t *testing.T) {
+	itemModel := models.ItemModel()
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	_, err := itemModel.InsertOne(item)
+	assert.Nil(t, err)
+
+	result := models.ItemSchema{}
+	err = itemModel.FindOne(bson.M{"_id": item.ID}, &result, yamgo.ReadPreferenceTags(bson.D{{Key: "region", Value: "eu-west"}}))
+
+	assert.Nil(t, err)
+	assert.Equal(t, item.ID, result.ID)
+
+	DropCollection("items")
+}
+
+func TestInvalidTagSet(t *testing.T) {
+	itemModel := models.ItemModel()
+	results := []models.ItemSchema{}
+
+	err := itemModel.Find(bson.M{}, &results, yamgo.ReadPreferenceTags(bson.D{{Key: "region", Value: 1}}))
+	assert.ErrorIs(t, err, yamgo.ErrInvalidTagSet)
+
+	err = itemModel.Find(bson.M{}, &results, yamgo.ReadPreferenceTags(bson.D{}))
+	assert.ErrorIs(t, err, yamgo.ErrInvalidTagSet)
+
+	assert.Panics(t, func() {
+		yamgo.NewModel("items", yamgo.WithReadPreferenceTags(bson.D{{Key: "", Value: "eu-west"}}))
+	})
+}
+
+func TestMaxStaleness(t *testing.T) {
+	itemModel := yamgo.NewModel("items", yamgo.WithMaxStaleness(2*time.Minute))
+
+	rp := itemModel.ReadPreference()
+	assert.Equal(t, readpref.SecondaryPreferredMode, rp.Mode())
+	maxStaleness, ok := rp.MaxStaleness()
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, maxStaleness)
+
+	taggedModel := yamgo.NewModel("items", yamgo.WithMaxStaleness(90*time.Second), yamgo.WithReadPreferenceTags(bson.D{{Key: "region", Value: "eu-west"}}))
+
+	rp = taggedModel.ReadPreference()
+	assert.Equal(t, readpref.NearestMode, rp.Mode())
+	maxStaleness, _ = rp.MaxStaleness()
+	assert.Equal(t, 90*time.Second, maxStaleness)
+
+	assert.PanicsWithError(t, "invalid max staleness: 1m0s is less than the 1m30s minimum", func() {
+		yamgo.NewModel("items", yamgo.WithMaxStaleness(time.Minute))
+	})
+}
+
+func TestMaxStalenessPerOperation(t *testing.T) {
+	itemModel := models.ItemModel()
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	_, err := itemModel.InsertOne(item)
+	assert.Nil(t, err)
+
+	result := models.ItemSchema{}
+	err = itemModel.FindOne(bson.M{"_id": item.ID}, &result, yamgo.MaxStaleness(90*time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, item.ID, result.ID)
+
+	err = itemModel.FindOne(bson.M{"_id": item.ID}, &result, yamgo.MaxStaleness(10*time.Second))
+	assert.ErrorIs(t, err, yamgo.ErrInvalidMaxStaleness)
+
+	DropCollection("items")
+}
@@ -0,0 +1,50 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nocfer/yamgo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+type invoiceSchema struct {
+	ID       uuid.UUID       `bson:"_id"`
+	Amount   decimal.Decimal `bson:"amount"`
+	IssuedAt time.Time       `bson:"issuedAt"`
+}
+
+func TestTypeRegistry(t *testing.T) {
+	invoiceModel := yamgo.NewModel("invoices", yamgo.WithTypeRegistry(yamgo.DefaultRegistry()))
+
+	invoice := invoiceSchema{
+		ID:       uuid.New(),
+		Amount:   decimal.RequireFromString("1234.56"),
+		IssuedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	_, err := invoiceModel.InsertOne(invoice)
+	assert.Nil(t, err)
+
+	var result invoiceSchema
+	err = invoiceModel.FindOne(bson.M{"_id": invoice.ID}, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, invoice.ID, result.ID)
+	assert.True(t, invoice.Amount.Equal(result.Amount))
+	assert.Equal(t, invoice.IssuedAt, result.IssuedAt)
+
+	raw, err := invoiceModel.NativeCollection().FindOne(context.TODO(), bson.M{}).DecodeBytes()
+	assert.Nil(t, err)
+
+	subtype, data := raw.Lookup("_id").Binary()
+	assert.Equal(t, bsontype.BinaryUUID, subtype)
+	assert.Equal(t, invoice.ID[:], data)
+	assert.Equal(t, bsontype.Decimal128, raw.Lookup("amount").Type)
+
+	DropCollection("invoices")
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var errNetwork = mongo.CommandError{Message: "connection reset", Labels: []string{"NetworkError"}}
+
+func TestRetryRead(t *testing.T) {
+	defer func(backoff time.Duration) { yamgo.RetryBackoff = backoff }(yamgo.RetryBackoff)
+	yamgo.RetryBackoff = time.Millisecond
+
+	calls := 0
+	var result string
+	err := yamgo.RetryRead(context.TODO(), 3, func(ctx context.Context) error {
+		calls++
+		if calls <= 2 {
+			return errNetwork
+		}
+		result = "third call"
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "third call", result)
+}
+
+func TestRetryReadGivesUp(t *testing.T) {
+	defer func(backoff time.Duration) { yamgo.RetryBackoff = backoff }(yamgo.RetryBackoff)
+	yamgo.RetryBackoff = time.Millisecond
+
+	calls := 0
+	err := yamgo.RetryRead(context.TODO(), 1, func(ctx context.Context) error {
+		calls++
+		return errNetwork
+	})
+
+	assert.True(t, mongo.IsNetworkError(err))
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryReadOtherErrors(t *testing.T) {
+	calls := 0
+	errOther := errors.New("not a network error")
+	err := yamgo.RetryRead(context.TODO(), 3, func(ctx context.Context) error {
+		calls++
+		return errOther
+	})
+
+	assert.Equal(t, errOther, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryableFind(t *testing.T) {
+	itemModel := models.ItemModel()
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+
+	_, err := itemModel.InsertOne(&item)
+	assert.Nil(t, err)
+
+	var found models.ItemSchema
+	err = itemModel.RetryableFindOne(context.TODO(), bson.M{"_id": item.ID}, &found, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, item.ID, found.ID)
+
+	var items []models.ItemSchema
+	err = itemModel.RetryableFind(context.TODO(), bson.M{}, &items, 2)
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+
+	count, err := itemModel.RetryableCount(context.TODO(), nil, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	DropCollection("items")
+}
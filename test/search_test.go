@@ -0,0 +1,41 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBuildSearchPipeline(t *testing.T) {
+	searchQuery := bson.M{"index": "default", "text": bson.M{"query": "coffee", "path": "name"}}
+
+	pipeline, err := yamgo.BuildSearchPipeline(yamgo.PaginationSearchParams{
+		PaginationFindParams: yamgo.PaginationFindParams{Limit: 10, Query: bson.M{"available": true}},
+		SearchQuery:          searchQuery,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, pipeline, 4)
+	assert.Equal(t, bson.D{{Key: "$search", Value: searchQuery}}, pipeline[0])
+	assert.Equal(t, "$match", pipeline[1][0].Key)
+	assert.Equal(t, bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: -1}}}}, pipeline[2])
+	assert.Equal(t, bson.D{{Key: "$limit", Value: int64(11)}}, pipeline[3])
+}
+
+func TestBuildSearchPipelineSortByScore(t *testing.T) {
+	pipeline, err := yamgo.BuildSearchPipeline(yamgo.PaginationSearchParams{
+		PaginationFindParams: yamgo.PaginationFindParams{Limit: 5},
+		SearchQuery:          bson.M{"text": bson.M{"query": "coffee", "path": "name"}},
+		SortByScore:          true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "$search", pipeline[0][0].Key)
+	assert.Equal(t, bson.D{{Key: "$addFields", Value: bson.M{yamgo.SearchScoreField: bson.M{"$meta": "searchScore"}}}}, pipeline[1])
+	assert.Equal(t, bson.D{{Key: "$sort", Value: bson.D{{Key: yamgo.SearchScoreField, Value: -1}, {Key: "_id", Value: -1}}}}, pipeline[3])
+}
+
+func TestBuildSearchPipelineValidation(t *testing.T) {
+	_, err := yamgo.BuildSearchPipeline(yamgo.PaginationSearchParams{PaginationFindParams: yamgo.PaginationFindParams{Limit: 5}})
+	assert.EqualError(t, err, "search query can't be empty")
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSeed(t *testing.T) {
+	docs := []interface{}{}
+	for i := 0; i < 5; i++ {
+		docs = append(docs, models.ItemSchema{ID: primitive.NewObjectID()})
+	}
+
+	itemModel := yamgo.NewModel("items", yamgo.WithSeedData(docs))
+
+	count, err := itemModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, count)
+
+	err = itemModel.Seed(context.TODO())
+	assert.Nil(t, err)
+
+	count, err = itemModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, count)
+
+	DropCollection("items")
+}
+
+func TestSeedConcurrent(t *testing.T) {
+	docs := []interface{}{}
+	for i := 0; i < 5; i++ {
+		docs = append(docs, models.ItemSchema{ID: primitive.NewObjectID()})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			itemModel := yamgo.NewModel("items", yamgo.WithSeedData(docs))
+			assert.Nil(t, itemModel.Seed(context.TODO()))
+		}()
+	}
+	wg.Wait()
+
+	itemModel := models.ItemModel()
+	count, err := itemModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, count)
+
+	DropCollection("items")
+}
+
+func TestSeedIfEmptySkipsPopulatedCollection(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	_, err := itemModel.InsertOne(models.ItemSchema{ID: primitive.NewObjectID()})
+	assert.Nil(t, err)
+
+	seeded := yamgo.NewModel("items", yamgo.WithSeedDataIfEmpty([]interface{}{models.ItemSchema{ID: primitive.NewObjectID()}}))
+
+	count, err := seeded.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	DropCollection("items")
+}
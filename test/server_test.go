@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestPing(t *testing.T) {
+	assert.Nil(t, yamgo.Ping(context.TODO()))
+}
+
+func TestServerSelectionTimeout(t *testing.T) {
+	clientOptions := options.Client().ApplyURI("mongodb://127.0.0.1:1")
+	assert.Nil(t, yamgo.WithServerSelectionTimeout(50*time.Millisecond)(clientOptions))
+
+	client, err := mongo.Connect(context.TODO(), clientOptions)
+	assert.Nil(t, err)
+
+	defer client.Disconnect(context.TODO())
+
+	start := time.Now()
+	err = yamgo.PingClient(context.TODO(), client)
+
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.ErrorIs(t, err, yamgo.ErrServerUnavailable)
+	assert.Contains(t, err.Error(), "yamgo: could not reach MongoDB after")
+
+	assert.Error(t, yamgo.WithServerSelectionTimeout(0)(options.Client()))
+}
+
+func TestOperationServerUnavailable(t *testing.T) {
+	clientOptions := options.Client().ApplyURI("mongodb://127.0.0.1:1")
+	assert.Nil(t, yamgo.WithServerSelectionTimeout(50*time.Millisecond)(clientOptions))
+
+	client, err := mongo.Connect(context.TODO(), clientOptions)
+	assert.Nil(t, err)
+
+	defer client.Disconnect(context.TODO())
+
+	// the error the driver returns to the model operations when no server can be selected
+	_, err = client.Database("test").Collection("items").CountDocuments(context.TODO(), bson.M{})
+
+	itemModel := yamgo.NewModel("items")
+	err = itemModel.WrapError("CountDocuments", err)
+
+	assert.ErrorIs(t, err, yamgo.ErrServerUnavailable)
+	assert.Contains(t, err.Error(), "yamgo [items.CountDocuments]: yamgo: could not reach MongoDB, check connection")
+}
+
+func TestSocketTimeoutAndHeartbeatInterval(t *testing.T) {
+	clientOptions := options.Client().ApplyURI(connectionURI)
+	assert.Nil(t, yamgo.WithSocketTimeout(5*time.Minute)(clientOptions))
+	assert.Nil(t, yamgo.WithHeartbeatInterval(time.Second)(clientOptions))
+
+	assert.Equal(t, 5*time.Minute, *clientOptions.SocketTimeout)
+	assert.Equal(t, time.Second, *clientOptions.HeartbeatInterval)
+
+	client, err := mongo.Connect(context.TODO(), clientOptions)
+	assert.Nil(t, err)
+	defer client.Disconnect(context.TODO())
+
+	assert.Nil(t, yamgo.PingClient(context.TODO(), client))
+
+	assert.ErrorIs(t, yamgo.WithSocketTimeout(-time.Second)(options.Client()), yamgo.ErrInvalidOption)
+	assert.ErrorIs(t, yamgo.WithHeartbeatInterval(100*time.Millisecond)(options.Client()), yamgo.ErrInvalidOption)
+}
+
+func TestValidateConnection(t *testing.T) {
+	itemModel := yamgo.NewModel("items")
+
+	report := itemModel.ValidateConnection(context.TODO())
+	assert.True(t, report.OK())
+	assert.True(t, report.Ping.OK)
+	assert.True(t, report.Write.OK)
+	assert.True(t, report.Read.OK)
+	assert.True(t, report.Delete.OK)
+	assert.Greater(t, report.Write.Latency, time.Duration(0))
+
+	count, err := itemModel.CountDocuments(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	DropCollection("items")
+}
+
+func TestValidateConnectionSchemaBlocked(t *testing.T) {
+	validator := bson.M{"$jsonSchema": bson.M{"required": bson.A{"email"}}}
+	err := yamgo.GetDB().Database.CreateCollection(context.TODO(), "users", options.CreateCollection().SetValidator(validator))
+	assert.Nil(t, err)
+
+	userModel := yamgo.NewModel("users")
+
+	report := userModel.ValidateConnection(context.TODO())
+	assert.True(t, report.Ping.OK)
+	assert.False(t, report.Write.OK)
+	assert.Equal(t, "write-blocked by schema", report.Write.Detail)
+	assert.False(t, report.Read.OK)
+	assert.False(t, report.OK())
+
+	DropCollection("users")
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestWithSession(t *testing.T) {
+	requireReplicaSet(t)
+
+	userModel := models.UserModel()
+
+	session, err := userModel.NativeClient().StartSession()
+	assert.Nil(t, err)
+	defer session.EndSession(context.TODO())
+
+	assert.Nil(t, session.StartTransaction())
+
+	ctx := yamgo.WithSession(context.TODO(), session)
+
+	_, inserted, err := userModel.InsertOneIfAbsent(ctx, models.UserSchema{Email: "jane@example.com"}, "email")
+	assert.Nil(t, err)
+	assert.True(t, inserted)
+
+	_, err = userModel.InsertOneContext(ctx, models.UserSchema{Email: "john@example.com"})
+	assert.Nil(t, err)
+
+	exists, err := userModel.Exists(ctx, bson.M{"email": "jane@example.com"})
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	var user models.UserSchema
+	assert.Nil(t, userModel.FindOneContext(ctx, bson.M{"email": "john@example.com"}, &user))
+	assert.Equal(t, "john@example.com", user.Email)
+
+	var byID models.UserSchema
+	assert.Nil(t, userModel.FindByObjectIDContext(ctx, user.ID, &byID))
+	assert.Equal(t, user, byID)
+
+	var users []models.UserSchema
+	assert.Nil(t, userModel.FindContext(ctx, bson.M{}, &users))
+	assert.Equal(t, 2, len(users))
+
+	var emails []bson.M
+	assert.Nil(t, userModel.AggregateContext(ctx, mongo.Pipeline{{{Key: "$project", Value: bson.M{"email": 1}}}}, &emails))
+	assert.Equal(t, 2, len(emails))
+
+	count, err := userModel.Count(ctx, bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	var page []models.UserSchema
+	_, err = userModel.PaginatedFindContext(ctx, yamgo.PaginationFindParams{Query: bson.M{}, Limit: 10}, &page)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(page))
+
+	// outside the transaction nothing is visible yet
+	count, err = userModel.Count(context.TODO(), bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	assert.Nil(t, session.AbortTransaction(context.TODO()))
+
+	exists, err = userModel.Exists(context.TODO(), bson.M{"email": "jane@example.com"})
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	DropCollection("users")
+}
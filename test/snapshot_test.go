@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSnapshot(t *testing.T) {
+	requireReplicaSet(t)
+
+	itemModel := models.ItemModel()
+
+	existing := models.ItemSchema{ID: primitive.NewObjectID()}
+	_, err := itemModel.InsertOne(existing)
+	assert.Nil(t, err)
+
+	since := time.Now().Add(-time.Minute)
+
+	err = itemModel.Snapshot(context.TODO(), func(snapshotCtx context.Context) error {
+		results := []models.ItemSchema{}
+		if err := itemModel.FindChangedSince(snapshotCtx, since, &results); err != nil {
+			return err
+		}
+		assert.Len(t, results, 1)
+
+		late := models.ItemSchema{ID: primitive.NewObjectID()}
+		if _, err := itemModel.InsertOne(late); err != nil {
+			return err
+		}
+
+		exists, err := itemModel.Exists(snapshotCtx, bson.M{"_id": late.ID})
+		if err != nil {
+			return err
+		}
+		assert.False(t, exists)
+
+		if err := itemModel.FindChangedSince(snapshotCtx, since, &results); err != nil {
+			return err
+		}
+		assert.Len(t, results, 1)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	count, err := itemModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	DropCollection("items")
+}
+
+func TestFindWithSnapshot(t *testing.T) {
+	requireReplicaSet(t)
+
+	itemModel := models.ItemModel()
+
+	docs := []interface{}{}
+	for i := 0; i < 150; i++ {
+		docs = append(docs, models.ItemSchema{ID: primitive.NewObjectID()})
+	}
+	_, err := itemModel.InsertMany(docs)
+	assert.Nil(t, err)
+
+	var once sync.Once
+	snapshotModel := yamgo.NewModel("items", yamgo.WithResultInterceptor(func(raw bson.Raw) (bson.Raw, error) {
+		// the first batch is decoded, insert before the cursor fetches the next one
+		once.Do(func() {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_, err := itemModel.InsertOne(models.ItemSchema{ID: primitive.NewObjectID()})
+				assert.Nil(t, err)
+			}()
+			<-done
+		})
+		return raw, nil
+	}))
+
+	results := []models.ItemSchema{}
+	err = snapshotModel.FindWithSnapshot(context.TODO(), bson.M{}, &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 150)
+
+	count, err := itemModel.CountDocuments(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 151, count)
+
+	DropCollection("items")
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type member struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Email     string             `bson:"email"`
+	DeletedAt *time.Time         `bson:"deletedAt,omitempty"`
+}
+
+func TestSoftDeleteScope(t *testing.T) {
+	memberModel := yamgo.NewModel("members", yamgo.WithSoftDelete("deletedAt"))
+
+	deletedAt := time.Now()
+	deleted := member{ID: primitive.NewObjectID(), Email: "gone@example.com", DeletedAt: &deletedAt}
+	active := member{ID: primitive.NewObjectID(), Email: "here@example.com"}
+	_, err := memberModel.InsertMany([]interface{}{deleted, active})
+	assert.Nil(t, err)
+
+	var result member
+	err = memberModel.FindOne(bson.M{"_id": deleted.ID}, &result)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	err = memberModel.FindOne(bson.M{"_id": deleted.ID}, &result, yamgo.IncludeDeleted())
+	assert.Nil(t, err)
+	assert.Equal(t, deleted.Email, result.Email)
+
+	result = member{}
+	err = memberModel.FindOne(bson.M{"_id": deleted.ID}, &result, yamgo.WithDeletedScope())
+	assert.Nil(t, err)
+	assert.Equal(t, deleted.Email, result.Email)
+
+	var results []member
+	assert.Nil(t, memberModel.Find(bson.M{}, &results))
+	assert.Len(t, results, 1)
+	assert.Nil(t, memberModel.Find(bson.M{}, &results, yamgo.IncludeDeleted()))
+	assert.Len(t, results, 2)
+
+	count, err := memberModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	count, err = memberModel.CountDocuments(bson.M{}, yamgo.IncludeDeleted())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	results = []member{}
+	page, err := memberModel.PaginatedFind(yamgo.PaginationFindParams{Limit: 10, CountTotal: true}, &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 1, page.Count)
+
+	results = []member{}
+	page, err = memberModel.PaginatedFind(yamgo.PaginationFindParams{Limit: 10, CountTotal: true, IncludeDeleted: true}, &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, 2, page.Count)
+
+	DropCollection("members")
+}
+
+func TestSoftDelete(t *testing.T) {
+	memberModel := yamgo.NewModel("members", yamgo.WithSoftDelete("deletedAt"))
+	ctx := context.Background()
+
+	_, err := memberModel.InsertMany([]interface{}{member{Email: "a@example.com"}, member{Email: "b@example.com"}})
+	assert.Nil(t, err)
+
+	n, err := memberModel.SoftDelete(ctx, bson.M{"email": "a@example.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	var result member
+	assert.Nil(t, memberModel.FindOne(bson.M{"email": "a@example.com"}, &result, yamgo.IncludeDeleted()))
+	assert.NotNil(t, result.DeletedAt)
+
+	// already deleted documents are left untouched
+	n, err = memberModel.SoftDelete(ctx, bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	plainModel := yamgo.NewModel("members")
+	_, err = plainModel.SoftDelete(ctx, bson.M{})
+	assert.NotNil(t, err)
+
+	DropCollection("members")
+}
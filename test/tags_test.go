@@ -0,0 +1,100 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+type jsonTaggedSchema struct {
+	FirstName string `json:"first_name"`
+	Age       int    `json:"age,omitempty"`
+	Secret    string `json:"-"`
+}
+
+func TestBSONTagName(t *testing.T) {
+	personModel := yamgo.NewModel("people", yamgo.WithBSONTagName("json"))
+
+	_, err := personModel.InsertOne(jsonTaggedSchema{FirstName: "Ada", Secret: "hidden"})
+	assert.Nil(t, err)
+
+	raw, err := personModel.NativeCollection().FindOne(context.TODO(), bson.M{}).DecodeBytes()
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Ada", raw.Lookup("first_name").StringValue())
+	_, err = raw.LookupErr("age")
+	assert.NotNil(t, err)
+	_, err = raw.LookupErr("secret")
+	assert.NotNil(t, err)
+
+	var result jsonTaggedSchema
+	err = personModel.FindOne(bson.M{"first_name": "Ada"}, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonTaggedSchema{FirstName: "Ada"}, result)
+
+	DropCollection("people")
+}
+
+type jsonTaggedDevice struct {
+	DeviceID uuid.UUID `json:"device_id"`
+}
+
+func TestBSONTagNameWithUUID(t *testing.T) {
+	// both options apply, whatever their order
+	deviceModel := yamgo.NewModel("devices", yamgo.WithBSONTagName("json"), yamgo.WithUUID())
+
+	device := jsonTaggedDevice{DeviceID: uuid.New()}
+	_, err := deviceModel.InsertOne(device)
+	assert.Nil(t, err)
+
+	raw, err := deviceModel.NativeCollection().FindOne(context.TODO(), bson.M{}).DecodeBytes()
+	assert.Nil(t, err)
+
+	subtype, _ := raw.Lookup("device_id").Binary()
+	assert.Equal(t, bsontype.BinaryUUID, subtype)
+
+	var result jsonTaggedDevice
+	err = deviceModel.FindOne(bson.M{"device_id": device.DeviceID}, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, device, result)
+
+	DropCollection("devices")
+}
+
+type dualTaggedSchema struct {
+	FirstName string `json:"first_name" bson:"firstName"`
+}
+
+func TestBSONTagNameSharedRegistry(t *testing.T) {
+	registry := yamgo.DefaultRegistry()
+
+	personModel := yamgo.NewModel("people", yamgo.WithTypeRegistry(registry), yamgo.WithBSONTagName("json"))
+	// the json tags don't leak into the other models of the registry
+	contactModel := yamgo.NewModel("contacts", yamgo.WithTypeRegistry(registry))
+
+	_, err := personModel.InsertOne(dualTaggedSchema{FirstName: "Ada"})
+	assert.Nil(t, err)
+	_, err = contactModel.InsertOne(dualTaggedSchema{FirstName: "Grace"})
+	assert.Nil(t, err)
+
+	raw, err := personModel.NativeCollection().FindOne(context.TODO(), bson.M{}).DecodeBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, "Ada", raw.Lookup("first_name").StringValue())
+
+	raw, err = contactModel.NativeCollection().FindOne(context.TODO(), bson.M{}).DecodeBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, "Grace", raw.Lookup("firstName").StringValue())
+
+	var result dualTaggedSchema
+	err = contactModel.FindOne(bson.M{"firstName": "Grace"}, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, "Grace", result.FirstName)
+
+	DropCollection("people")
+	DropCollection("contacts")
+}
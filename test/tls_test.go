@@ -0,0 +1,188 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func writeSelfSignedCert(t *testing.T) (certPath string, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "yamgo test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	certPath = filepath.Join(dir, "ca.pem")
+	keyPath = filepath.Join(dir, "ca.key")
+
+	assert.Nil(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	return certPath, keyPath
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	cfg := &tls.Config{ServerName: "mongo.example.com"}
+	clientOptions := options.Client()
+
+	err := yamgo.WithTLSConfig(cfg)(clientOptions)
+
+	assert.Nil(t, err)
+	assert.Same(t, cfg, clientOptions.TLSConfig)
+}
+
+func TestWithTLSFiles(t *testing.T) {
+	certPath, keyPath := writeSelfSignedCert(t)
+	clientOptions := options.Client()
+
+	assert.Nil(t, yamgo.WithTLSCAFile(certPath)(clientOptions))
+	assert.Nil(t, yamgo.WithTLSCertKeyFile(certPath, keyPath)(clientOptions))
+
+	assert.NotNil(t, clientOptions.TLSConfig.RootCAs)
+	assert.Len(t, clientOptions.TLSConfig.Certificates, 1)
+}
+
+func TestWithTLSMissingFiles(t *testing.T) {
+	clientOptions := options.Client()
+
+	assert.Error(t, yamgo.WithTLSCAFile("/does/not/exist.pem")(clientOptions))
+	assert.Error(t, yamgo.WithTLSCertKeyFile("/does/not/exist.pem", "/does/not/exist.key")(clientOptions))
+	assert.Error(t, yamgo.WithTLSConfig(nil)(clientOptions))
+}
+
+// writeServerCert writes to dir a self-signed CA and a certificate for localhost issued by it,
+// along with its key in the PEM file mongod expects.
+func writeServerCert(t *testing.T, dir string) (caPath string, serverPath string) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "yamgo test CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+
+	caDer, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	assert.Nil(t, err)
+	ca, err := x509.ParseCertificate(caDer)
+	assert.Nil(t, err)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	serverTemplate := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	serverDer, err := x509.CreateCertificate(rand.Reader, &serverTemplate, ca, &serverKey.PublicKey, caKey)
+	assert.Nil(t, err)
+
+	serverKeyDer, err := x509.MarshalPKCS8PrivateKey(serverKey)
+	assert.Nil(t, err)
+
+	caPath = filepath.Join(dir, "ca.pem")
+	serverPath = filepath.Join(dir, "server.pem")
+
+	serverPEM := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverDer}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: serverKeyDer})...,
+	)
+
+	// the files are read by the mongodb user of the container
+	assert.Nil(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer}), 0644))
+	assert.Nil(t, os.WriteFile(serverPath, serverPEM, 0644))
+
+	return caPath, serverPath
+}
+
+func TestTLSPing(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		t.Skipf("docker isn't available: %s", err)
+	}
+
+	dir := t.TempDir()
+	assert.Nil(t, os.Chmod(dir, 0755))
+	caPath, _ := writeServerCert(t, dir)
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "mongo",
+		Tag:        "5.0",
+		Cmd:        []string{"--bind_ip_all", "--tlsMode", "requireTLS", "--tlsCertificateKeyFile", "/etc/yamgo-tls/server.pem"},
+		Mounts:     []string{dir + ":/etc/yamgo-tls:ro"},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		t.Skipf("could not start a TLS mongod: %s", err)
+	}
+	defer pool.Purge(resource)
+
+	clientOptions := options.Client().ApplyURI(fmt.Sprintf("mongodb://localhost:%s/?directConnection=true", resource.GetPort("27017/tcp")))
+	assert.Nil(t, yamgo.WithTLSCAFile(caPath)(clientOptions))
+
+	client, err := mongo.Connect(context.TODO(), clientOptions)
+	assert.Nil(t, err)
+	defer client.Disconnect(context.TODO())
+
+	err = pool.Retry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return client.Ping(ctx, nil)
+	})
+	assert.Nil(t, err)
+
+	// a client without the CA can't verify the server
+	plainOptions := options.Client().ApplyURI(fmt.Sprintf("mongodb://localhost:%s/?directConnection=true&tls=true&serverSelectionTimeoutMS=2000", resource.GetPort("27017/tcp")))
+	plain, err := mongo.Connect(context.TODO(), plainOptions)
+	assert.Nil(t, err)
+	defer plain.Disconnect(context.TODO())
+
+	assert.NotNil(t, plain.Ping(context.TODO(), nil))
+}
@@ -0,0 +1,200 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestFindOneAndModifyUpdate(t *testing.T) {
+	foo := models.FooSchema{ID: primitive.NewObjectID(), Item: "before"}
+	fooModel := models.FooModel()
+
+	_, err := fooModel.InsertOne(&foo)
+	assert.Nil(t, err)
+
+	result := models.FooSchema{}
+	err = fooModel.FindOneAndModify(context.TODO(), bson.M{"_id": foo.ID}, bson.M{"$set": bson.M{"item": "after"}}, &result, yamgo.ModifyReturnNew())
+
+	assert.Nil(t, err)
+	assert.Equal(t, foo.ID, result.ID)
+	assert.Equal(t, "after", result.Item)
+
+	DropCollection("foos")
+}
+
+func TestFindOneAndModifyReplace(t *testing.T) {
+	foo := models.FooSchema{ID: primitive.NewObjectID(), Item: "before"}
+	fooModel := models.FooModel()
+
+	_, err := fooModel.InsertOne(&foo)
+	assert.Nil(t, err)
+
+	result := models.FooSchema{}
+	err = fooModel.FindOneAndModify(context.TODO(), bson.M{"_id": foo.ID}, models.FooSchema{Item: "replaced"}, &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "before", result.Item)
+
+	err = fooModel.FindByObjectID(foo.ID, &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "replaced", result.Item)
+
+	DropCollection("foos")
+}
+
+func TestFindOneAndModifyUpsert(t *testing.T) {
+	fooModel := models.FooModel()
+	id := primitive.NewObjectID()
+
+	result := models.FooSchema{}
+	err := fooModel.FindOneAndModify(context.TODO(), bson.M{"_id": id}, bson.M{"item": "created"}, &result, yamgo.ModifyUpsert(), yamgo.ModifyReturnNew())
+
+	assert.Nil(t, err)
+	assert.Equal(t, id, result.ID)
+	assert.Equal(t, "created", result.Item)
+
+	DropCollection("foos")
+}
+
+func TestBulkUpsert(t *testing.T) {
+	countryModel := yamgo.NewModel("countries")
+
+	countries := func(italyName string) []interface{} {
+		return []interface{}{
+			bson.M{"code": "FR", "name": "France"},
+			bson.M{"code": "DE", "name": "Germany"},
+			bson.M{"code": "IT", "name": italyName},
+			bson.M{"code": "ES", "name": "Spain"},
+			bson.M{"code": "PT", "name": "Portugal"},
+		}
+	}
+
+	res, err := countryModel.BulkUpsert(context.TODO(), countries("Italy"), []string{"code"})
+	assert.Nil(t, err)
+	assert.Equal(t, yamgo.BulkUpsertResult{InsertedCount: 5}, res)
+
+	res, err = countryModel.BulkUpsert(context.TODO(), countries("Italia"), []string{"code"})
+	assert.Nil(t, err)
+	assert.Equal(t, yamgo.BulkUpsertResult{MatchedCount: 5, ModifiedCount: 1}, res)
+
+	var italy bson.M
+	err = countryModel.FindOne(bson.M{"code": "IT"}, &italy)
+	assert.Nil(t, err)
+	assert.Equal(t, "Italia", italy["name"])
+
+	count, err := countryModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, count)
+
+	DropCollection("countries")
+}
+
+func TestFindOneAndIncrement(t *testing.T) {
+	counterModel := yamgo.NewModel("counters")
+
+	_, err := counterModel.NativeCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := counterModel.FindOneAndIncrement(context.TODO(), bson.M{"name": "orders"}, "seq", 2)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	value, err := counterModel.FindOneAndIncrement(context.TODO(), bson.M{"name": "orders"}, "seq", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), value)
+
+	value, err = counterModel.FindOneAndIncrement(context.TODO(), bson.M{"name": "invoices"}, "seq", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), value)
+
+	DropCollection("counters")
+}
+
+func TestConditionalUpdate(t *testing.T) {
+	scoreModel := yamgo.NewModel("scores")
+
+	high := primitive.NewObjectID()
+	low := primitive.NewObjectID()
+	_, err := scoreModel.InsertMany([]interface{}{
+		bson.M{"_id": high, "score": 20},
+		bson.M{"_id": low, "score": 5},
+	})
+	assert.Nil(t, err)
+
+	condition := bson.M{"score": bson.M{"$gt": 10}}
+	update := bson.M{"$set": bson.M{"rank": "gold"}}
+
+	updated, err := scoreModel.ConditionalUpdate(context.TODO(), bson.M{"_id": high}, condition, update)
+	assert.Nil(t, err)
+	assert.True(t, updated)
+
+	updated, err = scoreModel.ConditionalUpdate(context.TODO(), bson.M{"_id": low}, condition, update)
+	assert.Nil(t, err)
+	assert.False(t, updated)
+
+	var result bson.M
+	err = scoreModel.FindOne(bson.M{"_id": low}, &result)
+	assert.Nil(t, err)
+	assert.NotContains(t, result, "rank")
+
+	_, err = scoreModel.ConditionalUpdate(context.TODO(), bson.M{"_id": high}, condition, bson.M{"rank": "silver"})
+	assert.NotNil(t, err)
+
+	DropCollection("scores")
+}
+
+func TestUpdateOrInsert(t *testing.T) {
+	userModel := yamgo.NewModel("users")
+
+	var inserted bson.M
+	err := userModel.UpdateOrInsert(context.TODO(), bson.M{"email": "a@example.com"},
+		bson.M{"name": "Ada"},
+		bson.M{"createdBy": "signup"},
+		&inserted)
+	assert.Nil(t, err)
+	assert.Equal(t, "a@example.com", inserted["email"])
+	assert.Equal(t, "Ada", inserted["name"])
+	assert.Equal(t, "signup", inserted["createdBy"])
+
+	var updated bson.M
+	err = userModel.UpdateOrInsert(context.TODO(), bson.M{"email": "a@example.com"},
+		bson.M{"name": "Ada Lovelace"},
+		bson.M{"createdBy": "import"},
+		&updated)
+	assert.Nil(t, err)
+	assert.Equal(t, inserted["_id"], updated["_id"])
+	assert.Equal(t, "Ada Lovelace", updated["name"])
+	assert.Equal(t, "signup", updated["createdBy"])
+
+	var insertOnly bson.M
+	err = userModel.UpdateOrInsert(context.TODO(), bson.M{"email": "b@example.com"}, nil, bson.M{"createdBy": "import"}, &insertOnly)
+	assert.Nil(t, err)
+	assert.NotContains(t, insertOnly, "name")
+	assert.Equal(t, "import", insertOnly["createdBy"])
+
+	count, err := userModel.CountDocuments(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	DropCollection("users")
+}
@@ -2,8 +2,10 @@ package test
 
 import (
 	"context"
+	"testing"
 
 	"github.com/nocfer/yamgo"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func DropCollection(c string) {
@@ -13,3 +15,19 @@ func DropCollection(c string) {
 	}
 }
 
+// requireReplicaSet skips the test when the server isn't a replica set member,
+// which transactions, snapshot reads and change streams need.
+func requireReplicaSet(t *testing.T) {
+	t.Helper()
+
+	var hello struct {
+		SetName string `bson:"setName"`
+	}
+	err := yamgo.GetDB().Database.RunCommand(context.TODO(), bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		t.Fatalf("hello failed: %s", err)
+	}
+	if hello.SetName == "" {
+		t.Skip("the server isn't a replica set member")
+	}
+}
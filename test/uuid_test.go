@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nocfer/yamgo"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type deviceSchema struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	DeviceID uuid.UUID          `bson:"deviceID"`
+	Serial   [16]byte           `bson:"serial"`
+}
+
+func TestWithUUID(t *testing.T) {
+	deviceModel := yamgo.NewModel("devices", yamgo.WithUUID())
+
+	device := deviceSchema{ID: primitive.NewObjectID(), DeviceID: uuid.New(), Serial: uuid.New()}
+
+	_, err := deviceModel.InsertOne(device)
+	assert.Nil(t, err)
+
+	var result deviceSchema
+	err = deviceModel.FindOne(bson.M{"deviceID": device.DeviceID}, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, device, result)
+
+	raw, err := deviceModel.NativeCollection().FindOne(context.TODO(), bson.M{"_id": device.ID}).DecodeBytes()
+	assert.Nil(t, err)
+
+	subtype, data := raw.Lookup("deviceID").Binary()
+	assert.Equal(t, bsontype.BinaryUUID, subtype)
+	assert.Equal(t, device.DeviceID[:], data)
+
+	subtype, _ = raw.Lookup("serial").Binary()
+	assert.Equal(t, bsontype.BinaryUUID, subtype)
+
+	DropCollection("devices")
+}
+
+func TestWithUUIDAndTypeRegistry(t *testing.T) {
+	registry := bson.NewRegistry()
+
+	// the registry set after WithUUID doesn't drop its codec
+	invoiceModel := yamgo.NewModel("invoices", yamgo.WithUUID(), yamgo.WithTypeRegistry(registry))
+
+	_, err := invoiceModel.InsertOne(bson.M{"_id": uuid.New()})
+	assert.Nil(t, err)
+
+	raw, err := invoiceModel.NativeCollection().FindOne(context.TODO(), bson.M{}).DecodeBytes()
+	assert.Nil(t, err)
+
+	subtype, _ := raw.Lookup("_id").Binary()
+	assert.Equal(t, bsontype.BinaryUUID, subtype)
+
+	// the codec isn't registered on the shared registry
+	receiptModel := yamgo.NewModel("receipts", yamgo.WithTypeRegistry(registry))
+
+	_, err = receiptModel.InsertOne(bson.M{"_id": uuid.New()})
+	assert.Nil(t, err)
+
+	raw, err = receiptModel.NativeCollection().FindOne(context.TODO(), bson.M{}).DecodeBytes()
+	assert.Nil(t, err)
+
+	subtype, _ = raw.Lookup("_id").Binary()
+	assert.Equal(t, bsontype.BinaryGeneric, subtype)
+
+	assert.NotPanics(t, func() {
+		yamgo.NewModel("invoices", yamgo.WithUUID(), yamgo.WithTypeRegistry(bson.DefaultRegistry))
+	})
+
+	DropCollection("invoices")
+	DropCollection("receipts")
+}
+
+func TestNewUUID(t *testing.T) {
+	id := yamgo.NewUUID()
+	assert.Equal(t, bsontype.BinaryUUID, id.Subtype)
+	assert.Len(t, id.Data, 16)
+
+	_, err := uuid.FromBytes(id.Data)
+	assert.Nil(t, err)
+}
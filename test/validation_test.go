@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["email"],
+	"properties": {
+		"email": {"type": "string", "pattern": "^[^@]+@[^@]+$"}
+	}
+}`
+
+func TestValidateSchema(t *testing.T) {
+	userModel := yamgo.NewModel("users", yamgo.WithSchema([]byte(userSchema)))
+
+	_, err := userModel.InsertOne(bson.M{"email": "foo@bar.com"})
+	assert.Nil(t, err)
+
+	_, err = userModel.InsertOne(bson.M{"email": "foo"})
+	assert.ErrorIs(t, err, yamgo.ErrValidation)
+	assert.Contains(t, err.Error(), "/email")
+
+	_, err = userModel.InsertMany([]interface{}{bson.M{"email": "bar@foo.com"}, bson.M{"name": "bar"}})
+	assert.ErrorIs(t, err, yamgo.ErrValidation)
+
+	count, err := userModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	DropCollection("users")
+}
+
+func TestInvalidSchema(t *testing.T) {
+	assert.Panics(t, func() {
+		yamgo.NewModel("users", yamgo.WithSchema([]byte("{")))
+	})
+}
+
+func TestStructValidation(t *testing.T) {
+	userModel := models.UserModel(yamgo.WithStructValidation(validator.New()))
+
+	_, err := userModel.InsertOne(models.UserSchema{Email: "foo@bar.com"})
+	assert.Nil(t, err)
+
+	_, err = userModel.InsertOne(&models.UserSchema{})
+
+	var validationErr *yamgo.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.ErrorIs(t, err, yamgo.ErrValidation)
+
+	var fieldErrs validator.ValidationErrors
+	assert.ErrorAs(t, err, &fieldErrs)
+	assert.Equal(t, "Email", fieldErrs[0].Field())
+
+	result := models.UserSchema{}
+	err = userModel.FindOneAndModify(context.TODO(), bson.M{"email": "foo@bar.com"}, models.UserSchema{Email: "invalid"}, &result)
+	assert.ErrorIs(t, err, yamgo.ErrValidation)
+
+	count, err := userModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	DropCollection("users")
+}
@@ -0,0 +1,218 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mockChangeStream returns events, then fails with err.
+type mockChangeStream struct {
+	events  []bson.Raw
+	err     error
+	current bson.Raw
+}
+
+func (s *mockChangeStream) Next(ctx context.Context) bool {
+	if len(s.events) == 0 {
+		return false
+	}
+	s.current, s.events = s.events[0], s.events[1:]
+	return true
+}
+
+func (s *mockChangeStream) Current() bson.Raw {
+	return s.current
+}
+
+func (s *mockChangeStream) ResumeToken() bson.Raw {
+	return s.current.Lookup("_id").Document()
+}
+
+func (s *mockChangeStream) Err() error {
+	return s.err
+}
+
+func (s *mockChangeStream) Close(ctx context.Context) error {
+	return nil
+}
+
+// streamCall records the arguments a change stream was opened with.
+type streamCall struct {
+	pipeline mongo.Pipeline
+	opts     *options.ChangeStreamOptions
+	at       time.Time
+}
+
+// mockStreamOpener opens the results in order, recording every call.
+func mockStreamOpener(results ...func() (yamgo.ChangeStream, error)) (yamgo.ChangeStreamOpener, func() []streamCall) {
+	var mu sync.Mutex
+	var calls []streamCall
+
+	open := func(ctx context.Context, pipeline mongo.Pipeline, opts *options.ChangeStreamOptions) (yamgo.ChangeStream, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls = append(calls, streamCall{pipeline: pipeline, opts: opts, at: time.Now()})
+		if len(calls) > len(results) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return results[len(calls)-1]()
+	}
+
+	return open, func() []streamCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]streamCall(nil), calls...)
+	}
+}
+
+func streamOf(err error, events ...bson.Raw) func() (yamgo.ChangeStream, error) {
+	return func() (yamgo.ChangeStream, error) {
+		return &mockChangeStream{events: events, err: err}, nil
+	}
+}
+
+func openError(err error) func() (yamgo.ChangeStream, error) {
+	return func() (yamgo.ChangeStream, error) {
+		return nil, err
+	}
+}
+
+func changeEvent(token string) bson.Raw {
+	event, _ := bson.Marshal(bson.M{"_id": bson.M{"_data": token}, "operationType": "insert"})
+	return event
+}
+
+var resumableError = mongo.CommandError{Code: 6, Labels: []string{"ResumableChangeStreamError"}}
+
+func TestWatchWithAutoResume(t *testing.T) {
+	defer func(backoff time.Duration) { yamgo.RetryBackoff = backoff }(yamgo.RetryBackoff)
+	yamgo.RetryBackoff = time.Millisecond
+
+	itemModel := models.ItemModel()
+
+	open, calls := mockStreamOpener(
+		streamOf(resumableError, changeEvent("1")),
+		streamOf(nil, changeEvent("2")),
+	)
+
+	var received []string
+	err := itemModel.WatchWithAutoResume(context.TODO(), nil, func(event bson.Raw) error {
+		received = append(received, event.Lookup("_id", "_data").StringValue())
+		if len(received) == 2 {
+			return context.Canceled
+		}
+		return nil
+	}, yamgo.WatchStreamOpener(open))
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []string{"1", "2"}, received)
+
+	c := calls()
+	assert.Equal(t, 2, len(c))
+	assert.Nil(t, c[0].opts.ResumeAfter)
+	assert.Equal(t, changeEvent("1").Lookup("_id").Document(), c[1].opts.ResumeAfter)
+}
+
+func TestWatchWithAutoResumeNonResumableError(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	failure := mongo.CommandError{Code: 2, Message: "bad pipeline"}
+	open, calls := mockStreamOpener(streamOf(failure))
+
+	err := itemModel.WatchWithAutoResume(context.TODO(), nil, func(event bson.Raw) error {
+		return nil
+	}, yamgo.WatchStreamOpener(open))
+
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 1, len(calls()))
+}
+
+func TestWatchWithAutoResumeResetsBackoff(t *testing.T) {
+	defer func(backoff time.Duration) { yamgo.RetryBackoff = backoff }(yamgo.RetryBackoff)
+	yamgo.RetryBackoff = 50 * time.Millisecond
+
+	itemModel := models.ItemModel()
+
+	// three failed opens grow the backoff to 400ms, the events received afterwards bring it back to 50ms
+	open, calls := mockStreamOpener(
+		openError(resumableError),
+		openError(resumableError),
+		openError(resumableError),
+		streamOf(resumableError, changeEvent("1")),
+		streamOf(nil, changeEvent("2")),
+	)
+
+	err := itemModel.WatchWithAutoResume(context.TODO(), nil, func(event bson.Raw) error {
+		if event.Lookup("_id", "_data").StringValue() == "2" {
+			return context.Canceled
+		}
+		return nil
+	}, yamgo.WatchStreamOpener(open))
+
+	assert.Equal(t, context.Canceled, err)
+
+	c := calls()
+	assert.Equal(t, 5, len(c))
+	assert.Less(t, c[4].at.Sub(c[3].at), 300*time.Millisecond)
+}
+
+func TestWatchOnResumeTokenExpiry(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	freshPipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+
+	open, calls := mockStreamOpener(
+		streamOf(mongo.CommandError{Code: 286, Message: "resume point no longer in the oplog"}, changeEvent("1")),
+		streamOf(nil, changeEvent("2")),
+	)
+
+	expired := 0
+	var received []string
+	err := itemModel.WatchWithAutoResume(context.TODO(), nil, func(event bson.Raw) error {
+		received = append(received, event.Lookup("_id", "_data").StringValue())
+		if len(received) == 2 {
+			return context.Canceled
+		}
+		return nil
+	},
+		yamgo.WatchStreamOpener(open),
+		yamgo.OnResumeTokenExpiry(func() mongo.Pipeline {
+			expired++
+			return freshPipeline
+		}),
+	)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, []string{"1", "2"}, received)
+
+	c := calls()
+	assert.Equal(t, 2, len(c))
+	assert.Equal(t, freshPipeline, c[1].pipeline)
+	assert.Nil(t, c[1].opts.ResumeAfter)
+	assert.Nil(t, c[1].opts.StartAtOperationTime)
+}
+
+func TestWatchOnResumeTokenExpiryNotSet(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	expiry := mongo.CommandError{Code: 286, Message: "resume point no longer in the oplog"}
+	open, _ := mockStreamOpener(openError(expiry))
+
+	err := itemModel.WatchWithAutoResume(context.TODO(), nil, func(event bson.Raw) error {
+		return nil
+	}, yamgo.WatchStreamOpener(open))
+
+	assert.Equal(t, expiry, err)
+}
@@ -0,0 +1,26 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNativeAccessors(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	assert.Equal(t, "items", itemModel.NativeCollection().Name())
+	assert.Equal(t, yamgo.GetDB().Database.Name(), itemModel.NativeDatabase().Name())
+	assert.Same(t, yamgo.GetDB().Database.Client(), itemModel.NativeClient())
+	assert.Equal(t, itemModel.CollectionName(), itemModel.NativeCollection().Name())
+}
+
+func TestNamespace(t *testing.T) {
+	itemModel := models.ItemModel()
+
+	assert.Equal(t, "items", itemModel.CollectionName())
+	assert.Equal(t, "test", itemModel.DatabaseName())
+	assert.Equal(t, "test.items", itemModel.Namespace())
+}
@@ -0,0 +1,149 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nocfer/yamgo/test/models"
+	"github.com/nocfer/yamgo/yamgotest"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMustFindOne(t *testing.T) {
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+	itemModel := models.ItemModel()
+
+	_, err := itemModel.InsertOne(&item)
+	assert.Nil(t, err)
+
+	result := yamgotest.MustFindOne[models.ItemSchema](&itemModel, bson.M{"_id": item.ID})
+	assert.Equal(t, item.ID, result.ID)
+
+	results := yamgotest.MustFind[models.ItemSchema](&itemModel, bson.M{})
+	assert.Len(t, results, 1)
+
+	DropCollection("items")
+}
+
+func TestMustFindOnePanics(t *testing.T) {
+	itemModel := models.ItemModel()
+	missing := primitive.NewObjectID()
+
+	defer func() {
+		msg, ok := recover().(string)
+		assert.True(t, ok)
+		assert.Contains(t, msg, "items")
+		assert.Contains(t, msg, missing.Hex())
+	}()
+
+	yamgotest.MustFindOne[models.ItemSchema](&itemModel, bson.M{"_id": missing})
+}
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCompareBSON(t *testing.T) {
+	now := time.Now()
+	id := primitive.NewObjectID()
+
+	expected := bson.M{
+		"_id":       primitive.NewObjectID(),
+		"name":      "jane",
+		"age":       30,
+		"createdAt": now,
+		"address":   bson.M{"city": "Rome", "updatedAt": now},
+		"tags":      bson.A{"a", "b"},
+		"owner":     id,
+	}
+	actual := bson.M{
+		"_id":       primitive.NewObjectID(),
+		"name":      "jane",
+		"age":       int32(30),
+		"createdAt": primitive.NewDateTimeFromTime(now.Add(time.Millisecond)),
+		"address":   bson.D{{Key: "city", Value: "Rome"}, {Key: "updatedAt", Value: now}},
+		"tags":      bson.A{"a", "b"},
+		"owner":     id,
+	}
+
+	assert.False(t, yamgotest.CompareBSON(expected, actual))
+	assert.True(t, yamgotest.CompareBSON(expected, actual, yamgotest.IgnoreFields("_id"), yamgotest.TimeEpsilon(time.Second)))
+	assert.True(t, yamgotest.CompareBSON(expected, actual, yamgotest.IgnoreFields("_id", "createdAt")))
+
+	actual["address"] = bson.M{"city": "Milan", "updatedAt": now}
+	actual["tags"] = bson.A{"a"}
+
+	diffs := yamgotest.DiffBSON(expected, actual, yamgotest.IgnoreFields("_id", "createdAt"))
+	assert.Equal(t, []string{
+		"address.city: expected Rome (string), got Milan (string)",
+		"tags: expected 2 elements, got 1",
+	}, diffs)
+
+	assert.True(t, yamgotest.CompareBSON(expected, actual, yamgotest.IgnoreFields("_id", "createdAt", "address.city", "tags")))
+}
+
+func TestAssertBSONEqual(t *testing.T) {
+	yamgotest.AssertBSONEqual(t, bson.M{"_id": 1, "n": 2}, bson.M{"_id": 3, "n": int64(2)}, yamgotest.IgnoreFields("_id"))
+
+	tb := &recordingTB{TB: t}
+	ok := yamgotest.AssertBSONEqual(tb, bson.M{"n": 2}, bson.M{"n": 3, "extra": true}, "comparing %s", "counters")
+
+	assert.False(t, ok)
+	assert.Equal(t, []string{"comparing counters\nBSON documents differ:\n  extra: unexpected value true\n  n: expected 2, got 3"}, tb.errors)
+}
+
+func TestWithQueryCapture(t *testing.T) {
+	itemModel := models.ItemModel()
+	captured, queries := yamgotest.WithQueryCapture(&itemModel)
+	ctx := context.Background()
+
+	item := models.ItemSchema{ID: primitive.NewObjectID()}
+	_, err := captured.InsertOne(&item)
+	assert.Nil(t, err)
+
+	queries.Reset()
+	assert.Empty(t, queries.Queries())
+
+	var found models.ItemSchema
+	assert.Nil(t, captured.FindOne(bson.M{"_id": item.ID}, &found))
+
+	res, err := captured.UpdateOne(ctx, bson.M{"_id": item.ID}, bson.M{"$set": bson.M{"name": "updated"}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), res.ModifiedCount)
+
+	deleted, err := captured.DeleteOne(ctx, bson.M{"_id": item.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deleted.DeletedCount)
+
+	assert.True(t, queries.AssertQuery(t, "FindOne", bson.M{"_id": item.ID}))
+	assert.True(t, queries.AssertQuery(t, "UpdateOne", bson.M{"_id": item.ID}))
+	assert.True(t, queries.AssertQuery(t, "DeleteOne", bson.M{"_id": item.ID}))
+
+	recorded := queries.Queries()
+	assert.Len(t, recorded, 3)
+	assert.Equal(t, "items", recorded[0].Collection)
+	assert.Equal(t, int64(1), recorded[0].Count)
+	assert.Equal(t, bson.M{"$set": bson.M{"name": "updated"}}, recorded[1].Update)
+
+	// the original model isn't captured
+	_, err = itemModel.CountDocuments(bson.M{})
+	assert.Nil(t, err)
+	assert.Len(t, queries.Queries(), 3)
+
+	tb := &recordingTB{}
+	assert.False(t, queries.AssertQuery(tb, "FindOne", bson.M{"_id": primitive.NewObjectID()}))
+	assert.Len(t, tb.errors, 1)
+
+	DropCollection("items")
+}
@@ -0,0 +1,61 @@
+package yamgo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WithTLSConfig enables TLS on the client using the given configuration.
+func WithTLSConfig(tlsCfg *tls.Config) ClientOption {
+	return func(o *options.ClientOptions) error {
+		if tlsCfg == nil {
+			return errors.New("tls config can't be nil")
+		}
+		o.SetTLSConfig(tlsCfg)
+		return nil
+	}
+}
+
+// WithTLSCAFile enables TLS trusting the PEM encoded certificate authorities found in path.
+func WithTLSCAFile(path string) ClientOption {
+	return func(o *options.ClientOptions) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not read CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no valid certificate found in CA file %s", path)
+		}
+
+		clientTLSConfig(o).RootCAs = pool
+		return nil
+	}
+}
+
+// WithTLSCertKeyFile enables TLS authenticating the client with the given PEM encoded certificate and key.
+func WithTLSCertKeyFile(certPath, keyPath string) ClientOption {
+	return func(o *options.ClientOptions) error {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return fmt.Errorf("could not load client certificate: %w", err)
+		}
+
+		cfg := clientTLSConfig(o)
+		cfg.Certificates = append(cfg.Certificates, cert)
+		return nil
+	}
+}
+
+func clientTLSConfig(o *options.ClientOptions) *tls.Config {
+	if o.TLSConfig == nil {
+		o.SetTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	return o.TLSConfig
+}
@@ -0,0 +1,387 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FindOneAndModifyOption func(*findOneAndModifyOptions)
+
+type findOneAndModifyOptions struct {
+	upsert     bool
+	returnNew  bool
+	sort       interface{}
+	projection interface{}
+}
+
+// ModifyUpsert inserts the modification when no document matches the filter.
+func ModifyUpsert() FindOneAndModifyOption {
+	return func(o *findOneAndModifyOptions) {
+		o.upsert = true
+	}
+}
+
+// ModifyReturnNew decodes the modified document instead of the original one.
+func ModifyReturnNew() FindOneAndModifyOption {
+	return func(o *findOneAndModifyOptions) {
+		o.returnNew = true
+	}
+}
+
+// ModifySort selects which document is modified when several match the filter.
+func ModifySort(sort bson.D) FindOneAndModifyOption {
+	return func(o *findOneAndModifyOptions) {
+		o.sort = sort
+	}
+}
+
+// ModifyProjection limits the fields decoded into the result.
+func ModifyProjection(projection bson.M) FindOneAndModifyOption {
+	return func(o *findOneAndModifyOptions) {
+		o.projection = projection
+	}
+}
+
+// FindOneAndModify atomically modifies a single document and decodes it into result.
+// If any top-level key of modification starts with "$" it is sent as an update,
+// otherwise it is treated as a replacement document.
+func (mf *Model) FindOneAndModify(ctx context.Context, filter bson.M, modification interface{}, result interface{}, opts ...FindOneAndModifyOption) (err error) {
+	filter = mf.interceptQuery(filter)
+
+	var o findOneAndModifyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	isUpdate, err := isUpdateDocument(modification)
+	if err != nil {
+		return err
+	}
+
+	if err = mf.runBeforeUpdate(modification); err != nil {
+		return err
+	}
+
+	returnDocument := options.Before
+	if o.returnNew {
+		returnDocument = options.After
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindOneAndModify", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = 1
+		}
+		mf.observe("FindOneAndModify", filter, modification, count, start, err)
+	}(time.Now())
+
+	var res *mongo.SingleResult
+
+	if isUpdate {
+		updateOptions := options.FindOneAndUpdate().SetUpsert(o.upsert).SetReturnDocument(returnDocument)
+		if o.sort != nil {
+			updateOptions.SetSort(o.sort)
+		}
+		if o.projection != nil {
+			updateOptions.SetProjection(o.projection)
+		}
+		res = mf.col.FindOneAndUpdate(ctx, filter, modification, updateOptions)
+	} else {
+		if err = mf.Validate(modification); err != nil {
+			return err
+		}
+		replaceOptions := options.FindOneAndReplace().SetUpsert(o.upsert).SetReturnDocument(returnDocument)
+		if o.sort != nil {
+			replaceOptions.SetSort(o.sort)
+		}
+		if o.projection != nil {
+			replaceOptions.SetProjection(o.projection)
+		}
+		res = mf.col.FindOneAndReplace(ctx, filter, modification, replaceOptions)
+	}
+
+	if res.Err() != nil {
+		return mf.wrapQueryError("FindOneAndModify", filter, res.Err())
+	}
+
+	return res.Decode(result)
+}
+
+func isUpdateDocument(modification interface{}) (bool, error) {
+	// a pipeline is always an update
+	if _, ok := modification.(mongo.Pipeline); ok {
+		return true, nil
+	}
+
+	data, err := bson.Marshal(modification)
+	if err != nil {
+		return false, err
+	}
+
+	elements, err := bson.Raw(data).Elements()
+	if err != nil {
+		return false, err
+	}
+
+	for _, element := range elements {
+		if strings.HasPrefix(element.Key(), "$") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// BulkUpsertResult counts the documents inserted, matched and modified by BulkUpsert.
+type BulkUpsertResult struct {
+	InsertedCount int64
+	MatchedCount  int64
+	ModifiedCount int64
+}
+
+// BulkUpsert upserts documents in a single bulk write, matching existing documents on matchFields.
+// Matched documents get the fields of the new version set, _id is only written on insert.
+func (mf *Model) BulkUpsert(ctx context.Context, documents []interface{}, matchFields []string) (BulkUpsertResult, error) {
+	if len(matchFields) == 0 {
+		return BulkUpsertResult{}, errors.New("at least one match field is required")
+	}
+
+	if len(documents) == 0 {
+		return BulkUpsertResult{}, nil
+	}
+
+	writes := make([]mongo.WriteModel, len(documents))
+	for i, document := range documents {
+		if err := mf.runBeforeInsert(document); err != nil {
+			return BulkUpsertResult{}, err
+		}
+
+		raw, err := bson.MarshalWithRegistry(mf.bsonRegistry(), document)
+		if err != nil {
+			return BulkUpsertResult{}, err
+		}
+
+		filter, err := uniqueFilter(raw, matchFields)
+		if err != nil {
+			return BulkUpsertResult{}, err
+		}
+
+		elements, err := bson.Raw(raw).Elements()
+		if err != nil {
+			return BulkUpsertResult{}, err
+		}
+
+		set := bson.D{}
+		update := bson.D{}
+		for _, element := range elements {
+			if element.Key() == "_id" {
+				update = append(update, bson.E{Key: "$setOnInsert", Value: bson.D{{Key: "_id", Value: element.Value()}}})
+				continue
+			}
+			set = append(set, bson.E{Key: element.Key(), Value: element.Value()})
+		}
+		if len(set) > 0 {
+			update = append(update, bson.E{Key: "$set", Value: set})
+		}
+
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(mf.interceptQuery(filter)).
+			SetUpdate(update).
+			SetUpsert(true)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, LongTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("BulkUpsert", nil, time.Now())
+
+	res, err := mf.col.BulkWrite(ctx, writes)
+	if err != nil {
+		return BulkUpsertResult{}, err
+	}
+
+	return BulkUpsertResult{
+		InsertedCount: res.UpsertedCount,
+		MatchedCount:  res.MatchedCount,
+		ModifiedCount: res.ModifiedCount,
+	}, nil
+}
+
+// FindOneAndIncrement atomically adds increment to counterField of the document matching filter and returns the new value.
+// When no document matches, one is inserted with the counter starting at increment.
+func (mf *Model) FindOneAndIncrement(ctx context.Context, filter bson.M, counterField string, increment int64) (int64, error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	if counterField == "" {
+		return 0, errors.New("counter field can't be empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("FindOneAndIncrement", filter, time.Now())
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{counterField: 1})
+
+	// $inc creates the missing field, a $setOnInsert on the same path would conflict with it
+	raw, err := mf.col.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{counterField: increment}}, opts).Raw()
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := raw.LookupErr(strings.Split(counterField, ".")...)
+	if err != nil {
+		return 0, err
+	}
+
+	counter, ok := value.AsInt64OK()
+	if !ok {
+		return 0, fmt.Errorf("counter field %s is not a number: %v", counterField, value)
+	}
+
+	return counter, nil
+}
+
+// ConditionalUpdate applies update to the document matching both filter and condition, e.g. for compare-and-swap.
+// It returns false, without error, when no document matches.
+func (mf *Model) ConditionalUpdate(ctx context.Context, filter bson.M, condition bson.M, update bson.M) (updated bool, err error) {
+	if len(condition) > 0 {
+		if len(filter) > 0 {
+			filter = bson.M{"$and": bson.A{filter, condition}}
+		} else {
+			filter = condition
+		}
+	}
+
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	isUpdate, err := isUpdateDocument(update)
+	if err != nil {
+		return false, err
+	}
+	if !isUpdate {
+		return false, errors.New("update must only contain update operators")
+	}
+
+	if err = mf.runBeforeUpdate(update); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("ConditionalUpdate", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if updated {
+			count = 1
+		}
+		mf.observe("ConditionalUpdate", filter, update, count, start, err)
+	}(time.Now())
+
+	res, err := mf.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// UpdateOrInsert sets setFields on the document matching filter, or inserts a new document built from
+// the equality conditions of filter, setFields and insertFields when none matches. insertFields are only
+// written on insert, a field can't be in both. The resulting document is decoded into result.
+func (mf *Model) UpdateOrInsert(ctx context.Context, filter bson.M, setFields bson.M, insertFields bson.M, result interface{}) (err error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	update := bson.M{}
+	if len(setFields) > 0 {
+		update["$set"] = setFields
+	}
+	if len(insertFields) > 0 {
+		update["$setOnInsert"] = insertFields
+	}
+	if len(update) == 0 {
+		return errors.New("set fields and insert fields can't both be empty")
+	}
+
+	if err = mf.runBeforeUpdate(update); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("UpdateOrInsert", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = 1
+		}
+		mf.observe("UpdateOrInsert", filter, update, count, start, err)
+	}(time.Now())
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	res := mf.col.FindOneAndUpdate(ctx, filter, update, opts)
+	if res.Err() != nil {
+		return res.Err()
+	}
+
+	return mf.decodeSingleResult(res, result)
+}
+
+// UpdateOne applies update, which must only contain update operators, to the first document matching filter.
+func (mf *Model) UpdateOne(ctx context.Context, filter bson.M, update bson.M) (res *mongo.UpdateResult, err error) {
+	filter = mf.interceptQuery(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	isUpdate, err := isUpdateDocument(update)
+	if err != nil {
+		return nil, err
+	}
+	if !isUpdate {
+		return nil, errors.New("update must only contain update operators")
+	}
+
+	if err = mf.runBeforeUpdate(update); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MediumTimeout*time.Second)
+	defer cancel()
+	defer mf.logSlowQuery("UpdateOne", filter, time.Now())
+	defer func(start time.Time) {
+		var count int64
+		if err == nil {
+			count = res.MatchedCount
+		}
+		mf.observe("UpdateOne", filter, update, count, start, err)
+	}(time.Now())
+
+	res, err = mf.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, mf.wrapQueryError("UpdateOne", filter, err)
+	}
+
+	return res, nil
+}
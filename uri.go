@@ -0,0 +1,169 @@
+package yamgo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// URIBuilder builds MongoDB connection strings.
+type URIBuilder struct {
+	hosts          []string
+	srv            bool
+	user           string
+	password       string
+	hasCredential  bool
+	database       string
+	authSource     string
+	replicaSet     string
+	tls            *bool
+	tlsCAFile      string
+	maxPoolSize    int
+	connectTimeout time.Duration
+	readPreference string
+}
+
+func NewURIBuilder() *URIBuilder {
+	return &URIBuilder{}
+}
+
+// Host adds a host, optionally followed by ":port". It can be called several times for replica sets.
+func (b *URIBuilder) Host(h string) *URIBuilder {
+	b.hosts = append(b.hosts, h)
+	return b
+}
+
+// SRV switches the scheme to mongodb+srv.
+func (b *URIBuilder) SRV() *URIBuilder {
+	b.srv = true
+	return b
+}
+
+func (b *URIBuilder) Credential(user, password string) *URIBuilder {
+	b.user = user
+	b.password = password
+	b.hasCredential = true
+	return b
+}
+
+func (b *URIBuilder) Database(db string) *URIBuilder {
+	b.database = db
+	return b
+}
+
+func (b *URIBuilder) AuthSource(src string) *URIBuilder {
+	b.authSource = src
+	return b
+}
+
+func (b *URIBuilder) ReplicaSet(name string) *URIBuilder {
+	b.replicaSet = name
+	return b
+}
+
+func (b *URIBuilder) TLS(enabled bool) *URIBuilder {
+	b.tls = &enabled
+	return b
+}
+
+func (b *URIBuilder) TLSCAFile(path string) *URIBuilder {
+	b.tlsCAFile = path
+	return b
+}
+
+func (b *URIBuilder) MaxPoolSize(n int) *URIBuilder {
+	b.maxPoolSize = n
+	return b
+}
+
+func (b *URIBuilder) ConnectTimeout(d time.Duration) *URIBuilder {
+	b.connectTimeout = d
+	return b
+}
+
+func (b *URIBuilder) ReadPreference(pref string) *URIBuilder {
+	b.readPreference = pref
+	return b
+}
+
+// Build validates the configured values and returns the connection string.
+// User name and password are percent-encoded.
+func (b *URIBuilder) Build() (string, error) {
+
+	if len(b.hosts) == 0 {
+		return "", errors.New("at least one host is required")
+	}
+
+	for _, host := range b.hosts {
+		if host == "" {
+			return "", errors.New("host can't be empty")
+		}
+	}
+
+	u := url.URL{Scheme: "mongodb", Host: strings.Join(b.hosts, ",")}
+
+	if b.srv {
+		if len(b.hosts) != 1 {
+			return "", errors.New("an SRV connection string requires exactly one host")
+		}
+		if strings.Contains(b.hosts[0], ":") {
+			return "", errors.New("an SRV connection string can't specify a port")
+		}
+		u.Scheme = "mongodb+srv"
+	}
+
+	if b.hasCredential {
+		if b.user == "" {
+			return "", errors.New("credential requires a user")
+		}
+		u.User = url.UserPassword(b.user, b.password)
+	}
+
+	u.Path = "/" + b.database
+
+	query := url.Values{}
+
+	if b.authSource != "" {
+		query.Set("authSource", b.authSource)
+	}
+
+	if b.replicaSet != "" {
+		query.Set("replicaSet", b.replicaSet)
+	}
+
+	if b.tls != nil {
+		query.Set("tls", strconv.FormatBool(*b.tls))
+	}
+
+	if b.tlsCAFile != "" {
+		query.Set("tlsCAFile", b.tlsCAFile)
+	}
+
+	if b.maxPoolSize < 0 {
+		return "", errors.New("max pool size can't be negative")
+	} else if b.maxPoolSize > 0 {
+		query.Set("maxPoolSize", strconv.Itoa(b.maxPoolSize))
+	}
+
+	if b.connectTimeout < 0 {
+		return "", errors.New("connect timeout can't be negative")
+	} else if b.connectTimeout > 0 {
+		query.Set("connectTimeoutMS", strconv.FormatInt(b.connectTimeout.Milliseconds(), 10))
+	}
+
+	if b.readPreference != "" {
+		switch b.readPreference {
+		case "primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest":
+			query.Set("readPreference", b.readPreference)
+		default:
+			return "", fmt.Errorf("invalid read preference: %s", b.readPreference)
+		}
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
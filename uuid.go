@@ -0,0 +1,89 @@
+package yamgo
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	tUUID      = reflect.TypeOf(uuid.UUID{})
+	tUUIDBytes = reflect.TypeOf([16]byte{})
+)
+
+// WithUUID stores uuid.UUID and [16]byte values as BSON binary subtype 4.
+func WithUUID() Option {
+	return func(m *Model) error {
+		m.registryCodecs = append(m.registryCodecs, func(registry *bsoncodec.Registry) {
+			registerUUIDCodec(registry, tUUID, tUUIDBytes)
+		})
+		return nil
+	}
+}
+
+// NewUUID generates a random UUID as a BSON binary subtype 4.
+func NewUUID() primitive.Binary {
+	u := uuid.New()
+	return primitive.Binary{Subtype: bsontype.BinaryUUID, Data: u[:]}
+}
+
+func registerUUIDCodec(registry *bsoncodec.Registry, types ...reflect.Type) {
+	for _, t := range types {
+		registry.RegisterTypeEncoder(t, bsoncodec.ValueEncoderFunc(encodeUUID))
+		registry.RegisterTypeDecoder(t, bsoncodec.ValueDecoderFunc(decodeUUID))
+	}
+}
+
+func isUUIDType(t reflect.Type) bool {
+	return t == tUUID || t == tUUIDBytes
+}
+
+func encodeUUID(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || !isUUIDType(val.Type()) {
+		return bsoncodec.ValueEncoderError{Name: "UUIDEncodeValue", Types: []reflect.Type{tUUID, tUUIDBytes}, Received: val}
+	}
+
+	u := val.Convert(tUUIDBytes).Interface().([16]byte)
+
+	return vw.WriteBinaryWithSubtype(u[:], bsontype.BinaryUUID)
+}
+
+func decodeUUID(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || !isUUIDType(val.Type()) {
+		return bsoncodec.ValueDecoderError{Name: "UUIDDecodeValue", Types: []reflect.Type{tUUID, tUUIDBytes}, Received: val}
+	}
+
+	switch vr.Type() {
+	case bsontype.Null:
+		val.Set(reflect.Zero(val.Type()))
+		return vr.ReadNull()
+	case bsontype.Undefined:
+		val.Set(reflect.Zero(val.Type()))
+		return vr.ReadUndefined()
+	case bsontype.Binary:
+	default:
+		return fmt.Errorf("cannot decode %v into a UUID", vr.Type())
+	}
+
+	data, subtype, err := vr.ReadBinary()
+	if err != nil {
+		return err
+	}
+
+	if subtype != bsontype.BinaryUUID && subtype != bsontype.BinaryUUIDOld {
+		return fmt.Errorf("cannot decode binary subtype %v into a UUID", subtype)
+	}
+
+	u, err := uuid.FromBytes(data)
+	if err != nil {
+		return err
+	}
+
+	val.Set(reflect.ValueOf(u).Convert(val.Type()))
+	return nil
+}
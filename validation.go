@@ -0,0 +1,103 @@
+package yamgo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrValidation = errors.New("validation failed")
+
+// ValidationError wraps the errors reported by a struct validator.
+// It matches ErrValidation with errors.Is.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrValidation, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// WithSchema registers a JSON Schema that documents must satisfy before being inserted.
+func WithSchema(schema []byte) Option {
+	return func(m *Model) error {
+		compiled, err := jsonschema.CompileString(m.col.Name()+".schema.json", string(schema))
+		if err != nil {
+			return fmt.Errorf("invalid schema: %w", err)
+		}
+		m.schema = compiled
+		return nil
+	}
+}
+
+// Validate checks document against the schema registered with WithSchema.
+// The returned error wraps ErrValidation and reports the path of the failing field.
+func (mf *Model) Validate(document interface{}) error {
+	if mf.schema == nil {
+		return nil
+	}
+
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	extJSON, err := bson.MarshalExtJSON(bson.Raw(data), false, false)
+	if err != nil {
+		return err
+	}
+
+	var instance interface{}
+	if err = json.Unmarshal(extJSON, &instance); err != nil {
+		return err
+	}
+
+	err = mf.schema.Validate(instance)
+
+	var ve *jsonschema.ValidationError
+	if errors.As(err, &ve) {
+		for len(ve.Causes) > 0 {
+			ve = ve.Causes[0]
+		}
+		return fmt.Errorf("%w: %s: %s", ErrValidation, ve.InstanceLocation, ve.Message)
+	}
+
+	return err
+}
+
+// WithStructValidation validates structs with validate before they are inserted or used as replacements.
+// Other documents, like bson.M, are not validated.
+func WithStructValidation(validate *validator.Validate) Option {
+	return func(m *Model) error {
+		if validate == nil {
+			return errors.New("validator can't be nil")
+		}
+
+		hook := func(document interface{}) error {
+			if reflect.Indirect(reflect.ValueOf(document)).Kind() != reflect.Struct {
+				return nil
+			}
+			if err := validate.Struct(document); err != nil {
+				return &ValidationError{Err: err}
+			}
+			return nil
+		}
+
+		m.beforeInsert = append(m.beforeInsert, hook)
+		m.beforeUpdate = append(m.beforeUpdate, hook)
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package yamgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// server error codes of a change stream that cannot be resumed from its resume token
+const (
+	changeStreamFatalError  = 280
+	changeStreamHistoryLost = 286
+)
+
+// WatchOption configures WatchWithAutoResume.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	startAtOperationTime *primitive.Timestamp
+	onResumeTokenExpiry  func() mongo.Pipeline
+	open                 ChangeStreamOpener
+}
+
+// ChangeStream is the change stream WatchWithAutoResume reads the events from.
+// It is implemented by the driver change stream, see WatchStreamOpener to provide another one.
+type ChangeStream interface {
+	Next(ctx context.Context) bool
+	// Current returns the event Next moved to.
+	Current() bson.Raw
+	ResumeToken() bson.Raw
+	Err() error
+	Close(ctx context.Context) error
+}
+
+// ChangeStreamOpener opens a change stream running pipeline with opts.
+type ChangeStreamOpener func(ctx context.Context, pipeline mongo.Pipeline, opts *options.ChangeStreamOptions) (ChangeStream, error)
+
+type driverChangeStream struct {
+	*mongo.ChangeStream
+}
+
+func (s driverChangeStream) Current() bson.Raw {
+	return s.ChangeStream.Current
+}
+
+// WatchStartAtOperationTime starts the change stream at ts instead of now.
+func WatchStartAtOperationTime(ts primitive.Timestamp) WatchOption {
+	return func(o *watchOptions) {
+		o.startAtOperationTime = &ts
+	}
+}
+
+// OnResumeTokenExpiry is called when the change stream cannot be resumed because its resume point left the oplog.
+// The stream is then restarted from now with the pipeline fn returns, e.g. matching the events missed since the last one received.
+func OnResumeTokenExpiry(fn func() mongo.Pipeline) WatchOption {
+	return func(o *watchOptions) {
+		o.onResumeTokenExpiry = fn
+	}
+}
+
+// WatchStreamOpener opens the change streams with open instead of watching the model collection,
+// e.g. to run WatchWithAutoResume on a mocked stream.
+func WatchStreamOpener(open ChangeStreamOpener) WatchOption {
+	return func(o *watchOptions) {
+		o.open = open
+	}
+}
+
+// WatchWithAutoResume calls fn with every change event of the model collection matching pipeline until ctx is done
+// or fn returns an error. When the stream fails with a resumable error it is reopened after the last event received,
+// waiting longer after every consecutive failure until events are received again.
+func (mf *Model) WatchWithAutoResume(ctx context.Context, pipeline mongo.Pipeline, fn func(event bson.Raw) error, opts ...WatchOption) error {
+	o := watchOptions{
+		open: func(ctx context.Context, pipeline mongo.Pipeline, opts *options.ChangeStreamOptions) (ChangeStream, error) {
+			stream, err := mf.col.Watch(ctx, pipeline, opts)
+			if err != nil {
+				return nil, err
+			}
+			return driverChangeStream{stream}, nil
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if pipeline == nil {
+		pipeline = mongo.Pipeline{}
+	}
+
+	var resumeToken bson.Raw
+	backoff := RetryBackoff
+
+	for {
+		streamOptions := options.ChangeStream()
+		if resumeToken != nil {
+			streamOptions.SetResumeAfter(resumeToken)
+		} else if o.startAtOperationTime != nil {
+			streamOptions.SetStartAtOperationTime(o.startAtOperationTime)
+		}
+
+		stream, err := o.open(ctx, pipeline, streamOptions)
+		if err == nil {
+			var received bool
+			var fnErr error
+			received, fnErr, err = consumeChangeStream(ctx, stream, fn, &resumeToken)
+			if fnErr != nil {
+				return fnErr
+			}
+			if received {
+				backoff = RetryBackoff
+			}
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		switch {
+		case isResumeTokenExpired(err) && o.onResumeTokenExpiry != nil:
+			pipeline = o.onResumeTokenExpiry()
+			resumeToken = nil
+			o.startAtOperationTime = nil
+			continue
+		case isResumableChangeStreamError(err):
+		default:
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, LongTimeout*time.Second)
+	}
+}
+
+// consumeChangeStream passes the events of stream to fn, recording the resume token of each, until the stream fails.
+// It reports whether any event was received, and returns the error of fn apart from the stream one.
+func consumeChangeStream(ctx context.Context, stream ChangeStream, fn func(event bson.Raw) error, resumeToken *bson.Raw) (received bool, fnErr error, err error) {
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		received = true
+		if fnErr = fn(stream.Current()); fnErr != nil {
+			return received, fnErr, nil
+		}
+		*resumeToken = stream.ResumeToken()
+	}
+
+	return received, nil, stream.Err()
+}
+
+func isResumeTokenExpired(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) &&
+		(serverErr.HasErrorCode(changeStreamHistoryLost) || serverErr.HasErrorCode(changeStreamFatalError))
+}
+
+func isResumableChangeStreamError(err error) bool {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorLabel("ResumableChangeStreamError") {
+		return true
+	}
+	return mongo.IsNetworkError(err)
+}
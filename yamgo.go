@@ -4,21 +4,60 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/tag"
 )
 
 type Model struct {
 	col *mongo.Collection
+
+	slowQueryThreshold time.Duration
+	slowQueryLogger    *slog.Logger
+	filterSanitizer    func(bson.M) bson.M
+
+	schema       *jsonschema.Schema
+	beforeInsert []Hook
+	beforeUpdate []Hook
+
+	seedData []interface{}
+
+	maxConcurrency int
+	batchTimeout   time.Duration
+
+	readPrefTags      tag.Set
+	maxStaleness      time.Duration
+	registry          *bsoncodec.Registry
+	registryCodecs    []func(*bsoncodec.Registry)
+	queryInterceptor  func(bson.M) bson.M
+	resultInterceptor func(bson.Raw) (bson.Raw, error)
+
+	autoIndexMode    AutoIndexMode
+	autoIndexChecked *sync.Map
+
+	softDeleteField string
+
+	separateAddFields bool
+	errorEnrichment   bool
+
+	operationObserver func(OperationEvent)
 }
 
 type Mongo struct {
 	client   *mongo.Client
 	Database *mongo.Database
 	Err      error
+
+	autoEncryption *options.AutoEncryptionOptions
+	clientOptions  *options.ClientOptions
 }
 
 type ConnectionParams struct {
@@ -35,25 +74,50 @@ const (
 var _mongo Mongo
 
 // It connects to the database.
-func Connect(params ConnectionParams) {
+func Connect(params ConnectionParams, opts ...ClientOption) {
+	if err := connect(params, opts...); err != nil {
+		panic(err)
+	}
+}
+
+func connect(params ConnectionParams, opts ...ClientOption) error {
 	connectionURL := params.ConnectionUrl
 	dbName := params.DbName
 
 	if connectionURL == "" || dbName == "" {
-		panic(errors.New("cannot start db, missing connection parameters"))
+		return errors.New("cannot start db, missing connection parameters")
 	}
 
 	if _mongo.client == nil {
+		var clientOptions *options.ClientOptions
+		if clientOptions, _mongo.Err = newClientOptions(connectionURL, opts...); _mongo.Err != nil {
+			return _mongo.Err
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		_mongo.client, _mongo.Err = mongo.Connect(ctx, options.Client().ApplyURI(connectionURL))
-		if _mongo.Err == nil {
-			_mongo.Database = _mongo.client.Database(dbName)
-			fmt.Printf("Successfully connected to db! (%s)\n", dbName)
-		} else {
-			panic(_mongo.Err)
+		_mongo.client, _mongo.Err = mongo.Connect(ctx, clientOptions)
+		if _mongo.Err != nil {
+			return _mongo.Err
+		}
+
+		_mongo.Database = _mongo.client.Database(dbName)
+		_mongo.autoEncryption = clientOptions.AutoEncryptionOptions
+		_mongo.clientOptions = clientOptions
+		fmt.Printf("Successfully connected to db! (%s)\n", dbName)
+	}
+
+	return nil
+}
+
+func newClientOptions(connectionURL string, opts ...ClientOption) (*options.ClientOptions, error) {
+	clientOptions := options.Client().ApplyURI(connectionURL)
+	for _, opt := range opts {
+		if err := opt(clientOptions); err != nil {
+			return nil, err
 		}
 	}
+	return clientOptions, nil
 }
 
 func Disconnect() error {
@@ -69,6 +133,53 @@ func GetCollection(collectionName string) *mongo.Collection {
 	return _mongo.Database.Collection(collectionName)
 }
 
+// collectionOptions returns the options the model collection must be cloned with, nil if none.
+func (mf *Model) collectionOptions() *options.CollectionOptions {
+	rp := mf.ReadPreference()
+	if rp == nil && mf.registry == nil {
+		return nil
+	}
+
+	collectionOptions := options.Collection()
+	if rp != nil {
+		collectionOptions.SetReadPreference(rp)
+	}
+	if mf.registry != nil {
+		collectionOptions.SetRegistry(mf.registry)
+	}
+	return collectionOptions
+}
+
+func (mf *Model) CollectionName() string {
+	return mf.col.Name()
+}
+
+// DatabaseName returns the name of the database of the model collection.
+func (mf *Model) DatabaseName() string {
+	return mf.col.Database().Name()
+}
+
+// Namespace returns the "database.collection" name of the model collection.
+func (mf *Model) Namespace() string {
+	return mf.DatabaseName() + "." + mf.CollectionName()
+}
+
+// NativeCollection returns the underlying driver collection.
+// Operations run on it bypass yamgo options such as schema validation and slow query logging.
+func (mf *Model) NativeCollection() *mongo.Collection {
+	return mf.col
+}
+
+// NativeDatabase returns the driver database of the model collection, bypassing yamgo options like NativeCollection.
+func (mf *Model) NativeDatabase() *mongo.Database {
+	return mf.col.Database()
+}
+
+// NativeClient returns the driver client of the model collection, bypassing yamgo options like NativeCollection.
+func (mf *Model) NativeClient() *mongo.Client {
+	return mf.col.Database().Client()
+}
+
 func ToObjectID(hex string) primitive.ObjectID {
 	oId, err := primitive.ObjectIDFromHex(hex)
 
@@ -79,6 +190,40 @@ func ToObjectID(hex string) primitive.ObjectID {
 	return oId
 }
 
-func NewModel(collectionName string) Model {
-	return Model{col: GetCollection(collectionName)}
+// NewModel returns a Model bound to the given collection, configured by opts.
+// It panics if any of the options is invalid.
+func NewModel(collectionName string, opts ...Option) Model {
+	model, err := newModel(collectionName, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return model
+}
+
+func newModel(collectionName string, opts ...Option) (Model, error) {
+	model := Model{col: GetCollection(collectionName)}
+
+	for _, opt := range opts {
+		if err := opt(&model); err != nil {
+			return Model{}, err
+		}
+	}
+
+	if err := model.buildRegistry(); err != nil {
+		return Model{}, err
+	}
+
+	if collectionOptions := model.collectionOptions(); collectionOptions != nil {
+		col, err := model.col.Clone(collectionOptions)
+		if err != nil {
+			return Model{}, err
+		}
+		model.col = col
+	}
+
+	if err := model.Seed(context.Background()); err != nil {
+		return Model{}, err
+	}
+
+	return model, nil
 }
@@ -0,0 +1,68 @@
+package yamgotest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/nocfer/yamgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// QueryLog records the operations run by a model returned by WithQueryCapture.
+type QueryLog struct {
+	mu      sync.Mutex
+	queries []yamgo.OperationEvent
+}
+
+// WithQueryCapture returns a copy of m recording every operation it runs in the returned QueryLog.
+// m itself is left untouched.
+func WithQueryCapture(m *yamgo.Model) (*yamgo.Model, *QueryLog) {
+	log := &QueryLog{}
+
+	captured := *m
+	if err := yamgo.WithOperationObserver(log.record)(&captured); err != nil {
+		panic(fmt.Sprintf("yamgotest: WithQueryCapture on %s failed: %s", m.CollectionName(), err))
+	}
+
+	return &captured, log
+}
+
+func (l *QueryLog) record(event yamgo.OperationEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queries = append(l.queries, event)
+}
+
+// Queries returns the recorded operations, oldest first.
+func (l *QueryLog) Queries() []yamgo.OperationEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]yamgo.OperationEvent(nil), l.queries...)
+}
+
+// Reset clears the recorded operations.
+func (l *QueryLog) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queries = nil
+}
+
+// AssertQuery marks the test as failed if no op operation was recorded with filter, compared with CompareBSON.
+func (l *QueryLog) AssertQuery(t testing.TB, op string, filter bson.M) bool {
+	t.Helper()
+
+	queries := l.Queries()
+	for _, q := range queries {
+		if q.Operation == op && CompareBSON(filter, q.Filter) {
+			return true
+		}
+	}
+
+	recorded := make([]string, len(queries))
+	for i, q := range queries {
+		recorded[i] = fmt.Sprintf("%s %v", q.Operation, q.Filter)
+	}
+	t.Errorf("no %s query recorded with filter %v, recorded: %v", op, filter, recorded)
+	return false
+}
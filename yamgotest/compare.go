@@ -0,0 +1,234 @@
+package yamgotest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CompareOption configures CompareBSON and AssertBSONEqual.
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	ignored     map[string]bool
+	timeEpsilon time.Duration
+}
+
+// IgnoreFields skips the given fields. A dotted path skips a single nested field,
+// a plain name skips the field at any depth.
+func IgnoreFields(fields ...string) CompareOption {
+	return func(o *compareOptions) {
+		for _, field := range fields {
+			o.ignored[field] = true
+		}
+	}
+}
+
+// TimeEpsilon considers two times equal when they are at most epsilon apart.
+func TimeEpsilon(epsilon time.Duration) CompareOption {
+	return func(o *compareOptions) {
+		o.timeEpsilon = epsilon
+	}
+}
+
+// CompareBSON reports whether a and b hold the same fields and values. Numbers are compared by value
+// whatever their type, times by instant and nested documents and arrays field by field.
+func CompareBSON(a, b bson.M, opts ...CompareOption) bool {
+	return len(DiffBSON(a, b, opts...)) == 0
+}
+
+// DiffBSON returns one line per difference between expected and actual, empty when they are equal.
+func DiffBSON(expected, actual bson.M, opts ...CompareOption) []string {
+	o := compareOptions{ignored: map[string]bool{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var diffs []string
+	o.diffDocuments("", expected, actual, &diffs)
+	return diffs
+}
+
+// AssertBSONEqual marks the test as failed, listing the differences, if expected and actual differ.
+// CompareOption values found in msgAndArgs configure the comparison, the others form the failure message.
+func AssertBSONEqual(t testing.TB, expected, actual bson.M, msgAndArgs ...interface{}) bool {
+	t.Helper()
+
+	var opts []CompareOption
+	var message []interface{}
+	for _, arg := range msgAndArgs {
+		if opt, ok := arg.(CompareOption); ok {
+			opts = append(opts, opt)
+		} else {
+			message = append(message, arg)
+		}
+	}
+
+	diffs := DiffBSON(expected, actual, opts...)
+	if len(diffs) == 0 {
+		return true
+	}
+
+	msg := "BSON documents differ:\n  " + strings.Join(diffs, "\n  ")
+	if len(message) > 0 {
+		msg = formatMessage(message) + "\n" + msg
+	}
+	t.Errorf("%s", msg)
+	return false
+}
+
+func formatMessage(msgAndArgs []interface{}) string {
+	if format, ok := msgAndArgs[0].(string); ok && len(msgAndArgs) > 1 {
+		return fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+	return fmt.Sprint(msgAndArgs...)
+}
+
+func (o compareOptions) isIgnored(path, key string) bool {
+	return o.ignored[path] || o.ignored[key]
+}
+
+func (o compareOptions) diffDocuments(prefix string, expected, actual map[string]interface{}, diffs *[]string) {
+	keys := map[string]bool{}
+	for key := range expected {
+		keys[key] = true
+	}
+	for key := range actual {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if o.isIgnored(path, key) {
+			continue
+		}
+
+		e, inExpected := expected[key]
+		a, inActual := actual[key]
+
+		switch {
+		case !inActual:
+			*diffs = append(*diffs, fmt.Sprintf("%s: missing, expected %v", path, e))
+		case !inExpected:
+			*diffs = append(*diffs, fmt.Sprintf("%s: unexpected value %v", path, a))
+		default:
+			o.diffValues(path, e, a, diffs)
+		}
+	}
+}
+
+func (o compareOptions) diffValues(path string, expected, actual interface{}, diffs *[]string) {
+	if e, ok := toDocument(expected); ok {
+		if a, ok := toDocument(actual); ok {
+			o.diffDocuments(path, e, a, diffs)
+			return
+		}
+	}
+
+	if e, ok := toArray(expected); ok {
+		if a, ok := toArray(actual); ok {
+			if len(e) != len(a) {
+				*diffs = append(*diffs, fmt.Sprintf("%s: expected %d elements, got %d", path, len(e), len(a)))
+				return
+			}
+			for i := range e {
+				o.diffValues(fmt.Sprintf("%s.%d", path, i), e[i], a[i], diffs)
+			}
+			return
+		}
+	}
+
+	if e, ok := toTime(expected); ok {
+		if a, ok := toTime(actual); ok {
+			delta := e.Sub(a)
+			if delta < 0 {
+				delta = -delta
+			}
+			if delta > o.timeEpsilon {
+				*diffs = append(*diffs, fmt.Sprintf("%s: expected %v, got %v", path, e, a))
+			}
+			return
+		}
+	}
+
+	if e, ok := toNumber(expected); ok {
+		if a, ok := toNumber(actual); ok {
+			if e != a {
+				*diffs = append(*diffs, fmt.Sprintf("%s: expected %v, got %v", path, expected, actual))
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: expected %v (%T), got %v (%T)", path, expected, expected, actual, actual))
+	}
+}
+
+func toDocument(v interface{}) (map[string]interface{}, bool) {
+	switch d := v.(type) {
+	case bson.M:
+		return d, true
+	case map[string]interface{}:
+		return d, true
+	case bson.D:
+		m := make(map[string]interface{}, len(d))
+		for _, e := range d {
+			m[e.Key] = e.Value
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+func toArray(v interface{}) ([]interface{}, bool) {
+	switch a := v.(type) {
+	case bson.A:
+		return a, true
+	case []interface{}:
+		return a, true
+	}
+	return nil, false
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case primitive.DateTime:
+		return t.Time(), true
+	}
+	return time.Time{}, false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
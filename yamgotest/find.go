@@ -0,0 +1,31 @@
+// Package yamgotest provides helpers meant for tests only.
+package yamgotest
+
+import (
+	"fmt"
+
+	"github.com/nocfer/yamgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MustFindOne returns the document matching filter decoded as T, panicking if it can't be found or decoded.
+func MustFindOne[T any](m *yamgo.Model, filter bson.M) T {
+	var result T
+
+	if err := m.FindOne(filter, &result); err != nil {
+		panic(fmt.Sprintf("yamgotest: MustFindOne on %s with filter %v failed: %s", m.CollectionName(), filter, err))
+	}
+
+	return result
+}
+
+// MustFind returns the documents matching filter decoded as T, panicking on any error.
+func MustFind[T any](m *yamgo.Model, filter bson.M) []T {
+	results := []T{}
+
+	if err := m.Find(filter, &results); err != nil {
+		panic(fmt.Sprintf("yamgotest: MustFind on %s with filter %v failed: %s", m.CollectionName(), filter, err))
+	}
+
+	return results
+}